@@ -1,15 +1,44 @@
 package main
 
 import (
+	"errors"
 	"fmt"
 	"io"
+	"log/slog"
 	"os"
+	"path/filepath"
+	"strings"
 
 	"github.com/boattime/awsl/internal/eval"
 	"github.com/boattime/awsl/internal/lexer"
 	"github.com/boattime/awsl/internal/parser"
 )
 
+// usage is printed when the command line is invalid.
+const usage = `usage: awsl [options] <script.awsl> [args...]
+       awsl [options] -e SOURCE [args...]
+       awsl [options] - [args...]      (script on stdin)
+       awsl [options] test [path...]
+       awsl [options]                  (interactive)
+options: [--read-only] [--log-level=LEVEL] [--log-format=text|json]`
+
+// logLevels maps --log-level values to levels.
+var logLevels = map[string]slog.Level{
+	"debug": slog.LevelDebug,
+	"info":  slog.LevelInfo,
+	"warn":  slog.LevelWarn,
+	"error": slog.LevelError,
+}
+
+// Exit codes. Scripts choose their own codes with exit(code); codes 3 to
+// 63 are never used by awsl itself.
+const (
+	exitOK           = 0
+	exitRuntimeError = 1  // the script failed while running
+	exitUsage        = 2  // the command line was invalid or the script unreadable
+	exitParseError   = 65 // the script has syntax errors
+)
+
 // Version information (set via ldflags during build).
 var (
 	Version   = "dev"
@@ -17,33 +46,81 @@ var (
 )
 
 func main() {
-	os.Exit(run(os.Args, os.Stdout, os.Stderr))
+	os.Exit(run(os.Args, os.Stdin, os.Stdout, os.Stderr))
 }
 
-// run executes the AWSL interpreter with the given arguments and writers.
-// It returns an exit code (0 for success, non-zero for errors).
+// run executes the AWSL interpreter with the given arguments and streams;
+// without a script it starts the REPL on stdin.
+// It returns the process exit code: exitOK on success, the code passed to
+// exit, or one of the exit codes above for the kind of failure.
 // This function is separated from main() to enable testing.
-func run(args []string, stdout, stderr io.Writer) int {
-	if len(args) < 2 {
-		fmt.Fprintln(stderr, "usage: awsl <script.awsl>")
-		return 1
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	readOnly := false
+	logLevel, logFormat := "info", "text"
+
+	// Options come before the script name, "-" or -e; arguments after
+	// the script belong to it
+	i := 1
+	for ; i < len(args) && isOption(args[i]); i++ {
+		arg := args[i]
+		option, value, hasValue := strings.Cut(arg, "=")
+		switch {
+		case arg == "--version" || arg == "-v":
+			fmt.Fprintf(stdout, "awsl version %s (commit: %s)\n", Version, GitCommit)
+			return exitOK
+		case arg == "--read-only":
+			readOnly = true
+		case option == "--log-level" || option == "--log-format":
+			if !hasValue {
+				if i+1 == len(args) {
+					fmt.Fprintf(stderr, "option %s requires a value\n", option)
+					fmt.Fprintln(stderr, usage)
+					return exitUsage
+				}
+				i++
+				value = args[i]
+			}
+			if option == "--log-level" {
+				logLevel = value
+			} else {
+				logFormat = value
+			}
+		default:
+			fmt.Fprintf(stderr, "unknown option: %s\n", arg)
+			fmt.Fprintln(stderr, usage)
+			return exitUsage
+		}
+	}
+
+	level, ok := logLevels[logLevel]
+	if !ok {
+		fmt.Fprintf(stderr, "invalid --log-level: %q (want debug, info, warn or error)\n", logLevel)
+		return exitUsage
+	}
+	if logFormat != "text" && logFormat != "json" {
+		fmt.Fprintf(stderr, "invalid --log-format: %q (want text or json)\n", logFormat)
+		return exitUsage
 	}
 
-	// Handle version flag
-	if args[1] == "--version" || args[1] == "-v" {
-		fmt.Fprintf(stdout, "awsl version %s (commit: %s)\n", Version, GitCommit)
-		return 0
+	cfg := config{
+		readOnly: readOnly,
+		logger:   eval.NewLogger(stderr, level, logFormat == "json"),
+	}
+	if i >= len(args) {
+		return runREPL(cfg, stdin, stdout, stderr)
+	}
+	if args[i] == "test" {
+		return runTests(args[i+1:], cfg, stdout, stderr)
 	}
 
-	filename := args[1]
-	source, err := os.ReadFile(filename)
+	source, dir, scriptArgs, err := readScript(args[i:], stdin)
 	if err != nil {
-		fmt.Fprintf(stderr, "error reading file: %v\n", err)
-		return 1
+		fmt.Fprintln(stderr, err)
+		return exitUsage
 	}
 
-	// Lex and parse the source file
-	l := lexer.New(string(source))
+	// Lex and parse the source
+	l := lexer.New(source)
 	p := parser.New(l)
 	program := p.ParseProgram()
 
@@ -52,21 +129,93 @@ func run(args []string, stdout, stderr io.Writer) int {
 		for _, parseErr := range p.Errors() {
 			fmt.Fprintln(stderr, parseErr)
 		}
-		return 1
+		return exitParseError
 	}
 
-	env := eval.NewEnvironment(stdout)
-	eval.RegisterBuiltins(env)
+	env := cfg.newEnvironment(dir, stdout, scriptArgs)
 	result := eval.Eval(program, env)
 
-	if result.Type() == eval.ERROR_OBJ {
-		fmt.Fprintln(stderr, result.Inspect())
-		return 1
+	if errObj, ok := result.(*eval.Error); ok {
+		return errorExitCode(errObj, stderr)
 	}
 
 	if result != eval.NULL {
 		fmt.Fprintln(stdout, result.Inspect())
 	}
 
-	return 0
+	return exitOK
+}
+
+// isOption reports whether a command-line argument before the script is
+// an option for awsl itself.
+func isOption(arg string) bool {
+	return strings.HasPrefix(arg, "-") && arg != "-" && arg != "-e"
+}
+
+// readScript returns the source of the script that args start with, the
+// absolute directory that file builtins resolve its relative paths
+// against, and the script's arguments. The script is a file, "-" to read
+// it from stdin, or -e followed by the source itself; the last two
+// resolve paths against the current directory.
+func readScript(args []string, stdin io.Reader) (string, string, []string, error) {
+	var source []byte
+	dir := "."
+	rest := args[1:]
+
+	switch args[0] {
+	case "-e":
+		if len(rest) == 0 {
+			return "", "", nil, errors.New("option -e requires a value")
+		}
+		source = []byte(rest[0])
+		rest = rest[1:]
+	case "-":
+		var err error
+		source, err = io.ReadAll(stdin)
+		if err != nil {
+			return "", "", nil, fmt.Errorf("error reading stdin: %w", err)
+		}
+	default:
+		var err error
+		source, err = os.ReadFile(args[0])
+		if err != nil {
+			return "", "", nil, fmt.Errorf("error reading file: %w", err)
+		}
+		dir = filepath.Dir(args[0])
+	}
+
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", "", nil, fmt.Errorf("error resolving script directory: %w", err)
+	}
+	return string(source), dir, rest, nil
+}
+
+// config holds the options that apply to every script awsl runs.
+type config struct {
+	readOnly bool
+	logger   *slog.Logger
+}
+
+// newEnvironment returns an environment for a script in dir, with the
+// builtins registered and arguments bound to args and flags.
+func (c config) newEnvironment(dir string, stdout io.Writer, arguments []string) *eval.Environment {
+	env := eval.NewEnvironment(stdout)
+	env.SetDir(dir)
+	env.SetDenyWrites(c.readOnly)
+	env.SetLogger(c.logger)
+	eval.RegisterBuiltins(env)
+	eval.RegisterScriptArgs(env, arguments)
+	return env
+}
+
+// errorExitCode reports an error that stopped the script and returns the
+// exit code for its category. A call to exit is not reported.
+func errorExitCode(err *eval.Error, stderr io.Writer) int {
+	if err.Category == eval.ExitRequest {
+		return err.ExitCode
+	}
+
+	fmt.Fprintln(stderr, err.Inspect())
+	return exitRuntimeError
 }
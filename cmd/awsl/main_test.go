@@ -7,30 +7,32 @@ import (
 	"path/filepath"
 	"strings"
 	"testing"
+
+	"github.com/boattime/awsl/internal/eval"
 )
 
 // update is a flag to update golden files with current output.
 // Run with: make test-update
 var update = flag.Bool("update", false, "update golden files")
 
-func TestRun_NoArgs(t *testing.T) {
+func TestRun_NoArgsStartsREPL(t *testing.T) {
 	var stdout, stderr bytes.Buffer
 
-	exitCode := run([]string{"awsl"}, &stdout, &stderr)
+	exitCode := run([]string{"awsl"}, strings.NewReader("1 + 2\n"), &stdout, &stderr)
 
-	if exitCode != 1 {
-		t.Errorf("expected exit code 1, got %d", exitCode)
+	if exitCode != exitOK {
+		t.Errorf("expected exit code %d, got %d", exitOK, exitCode)
 	}
 
-	if !strings.Contains(stderr.String(), "usage:") {
-		t.Errorf("expected usage message in stderr, got %q", stderr.String())
+	if stdout.String() != "3\n" {
+		t.Errorf("expected REPL output %q, got %q (stderr %q)", "3\n", stdout.String(), stderr.String())
 	}
 }
 
 func TestRun_Version(t *testing.T) {
 	var stdout, stderr bytes.Buffer
 
-	exitCode := run([]string{"awsl", "--version"}, &stdout, &stderr)
+	exitCode := run([]string{"awsl", "--version"}, nil, &stdout, &stderr)
 
 	if exitCode != 0 {
 		t.Errorf("expected exit code 0, got %d", exitCode)
@@ -44,10 +46,10 @@ func TestRun_Version(t *testing.T) {
 func TestRun_FileNotFound(t *testing.T) {
 	var stdout, stderr bytes.Buffer
 
-	exitCode := run([]string{"awsl", "nonexistent.awsl"}, &stdout, &stderr)
+	exitCode := run([]string{"awsl", "nonexistent.awsl"}, nil, &stdout, &stderr)
 
-	if exitCode != 1 {
-		t.Errorf("expected exit code 1, got %d", exitCode)
+	if exitCode != exitUsage {
+		t.Errorf("expected exit code %d, got %d", exitUsage, exitCode)
 	}
 
 	if !strings.Contains(stderr.String(), "error reading file") {
@@ -55,6 +57,246 @@ func TestRun_FileNotFound(t *testing.T) {
 	}
 }
 
+func TestRun_UnknownOption(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+
+	exitCode := run([]string{"awsl", "--bogus", "script.awsl"}, nil, &stdout, &stderr)
+
+	if exitCode != exitUsage {
+		t.Errorf("expected exit code %d, got %d", exitUsage, exitCode)
+	}
+
+	if !strings.Contains(stderr.String(), "unknown option: --bogus") {
+		t.Errorf("expected unknown option message in stderr, got %q", stderr.String())
+	}
+}
+
+func TestRun_PathsRelativeToScript(t *testing.T) {
+	dir := t.TempDir()
+	script := filepath.Join(dir, "script.awsl")
+	writeFile(t, script, `write_file("out.txt", "hello"); print(read_file("out.txt"));`)
+
+	var stdout, stderr bytes.Buffer
+	exitCode := run([]string{"awsl", script}, nil, &stdout, &stderr)
+
+	if exitCode != 0 {
+		t.Fatalf("expected exit code 0, got %d (stderr: %q)", exitCode, stderr.String())
+	}
+
+	if stdout.String() != "hello\n" {
+		t.Errorf("expected hello, got %q", stdout.String())
+	}
+
+	if _, err := os.Stat(filepath.Join(dir, "out.txt")); err != nil {
+		t.Errorf("expected out.txt next to the script: %v", err)
+	}
+}
+
+func TestRun_ReadOnly(t *testing.T) {
+	dir := t.TempDir()
+	script := filepath.Join(dir, "script.awsl")
+	writeFile(t, script, `write_file("out.txt", "hello");`)
+
+	var stdout, stderr bytes.Buffer
+	exitCode := run([]string{"awsl", "--read-only", script}, nil, &stdout, &stderr)
+
+	if exitCode != 1 {
+		t.Errorf("expected exit code 1, got %d", exitCode)
+	}
+
+	if !strings.Contains(stderr.String(), "filesystem writes are disabled") {
+		t.Errorf("expected write denial in stderr, got %q", stderr.String())
+	}
+
+	if _, err := os.Stat(filepath.Join(dir, "out.txt")); !os.IsNotExist(err) {
+		t.Errorf("expected out.txt not to be written, got %v", err)
+	}
+}
+
+func TestRun_ScriptArguments(t *testing.T) {
+	t.Setenv("AWSL_TEST_OWNER", "platform")
+	dir := t.TempDir()
+	script := filepath.Join(dir, "script.awsl")
+	writeFile(t, script, `print(args, flags, env("AWSL_TEST_OWNER"), env("AWSL_TEST_UNSET", "none"));`)
+
+	var stdout, stderr bytes.Buffer
+	exitCode := run([]string{"awsl", "--read-only", script, "--stage", "prod", "extra", "--dry-run"}, nil, &stdout, &stderr)
+
+	if exitCode != 0 {
+		t.Fatalf("expected exit code 0, got %d (stderr: %q)", exitCode, stderr.String())
+	}
+
+	expected := "[extra] {stage: prod, dry_run: true} platform none\n"
+	if stdout.String() != expected {
+		t.Errorf("expected %q, got %q", expected, stdout.String())
+	}
+}
+
+func TestRun_ExitCodes(t *testing.T) {
+	tests := []struct {
+		name           string
+		source         string
+		expectedCode   int
+		expectedStdout string
+		expectedStderr string
+	}{
+		{"success", `print("ok");`, exitOK, "ok\n", ""},
+		{"exit", `print("found"); exit(3); print("unreachable");`, 3, "found\n", ""},
+		{"exit default", `exit();`, exitOK, "", ""},
+		{"exit in callback", `fn check(x) { if (x > 1) { exit(4); } return x; } map([1, 2, 3], check);`, 4, "", ""},
+		{"runtime error", `x = 1 + "a";`, exitRuntimeError, "", "type mismatch"},
+		{"bad exit code", `exit(256);`, exitRuntimeError, "", "exit code must be between 0 and 255"},
+		{"parse error", `x = ;`, exitParseError, "", "line 1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			script := filepath.Join(t.TempDir(), "script.awsl")
+			writeFile(t, script, tt.source)
+
+			var stdout, stderr bytes.Buffer
+			exitCode := run([]string{"awsl", script}, nil, &stdout, &stderr)
+
+			if exitCode != tt.expectedCode {
+				t.Errorf("expected exit code %d, got %d (stderr: %q)", tt.expectedCode, exitCode, stderr.String())
+			}
+			if stdout.String() != tt.expectedStdout {
+				t.Errorf("expected stdout %q, got %q", tt.expectedStdout, stdout.String())
+			}
+			if tt.expectedStderr == "" && stderr.Len() > 0 || !strings.Contains(stderr.String(), tt.expectedStderr) {
+				t.Errorf("expected stderr containing %q, got %q", tt.expectedStderr, stderr.String())
+			}
+		})
+	}
+}
+
+func TestErrorExitCode(t *testing.T) {
+	tests := []struct {
+		name           string
+		err            *eval.Error
+		expectedCode   int
+		expectedStderr string
+	}{
+		{"runtime error", &eval.Error{Message: "division by zero", Line: 1, Column: 1}, exitRuntimeError, "error at line 1, column 1: division by zero\n"},
+		{"assertion", &eval.Error{Message: "assertion failed: x", Line: 2, Column: 1, Category: eval.AssertionError}, exitRuntimeError, "error at line 2, column 1: assertion failed: x\n"},
+		{"exit", &eval.Error{Category: eval.ExitRequest, ExitCode: 7}, 7, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stderr bytes.Buffer
+			if code := errorExitCode(tt.err, &stderr); code != tt.expectedCode {
+				t.Errorf("expected exit code %d, got %d", tt.expectedCode, code)
+			}
+			if stderr.String() != tt.expectedStderr {
+				t.Errorf("expected stderr %q, got %q", tt.expectedStderr, stderr.String())
+			}
+		})
+	}
+}
+
+func TestRun_LogOptions(t *testing.T) {
+	script := filepath.Join(t.TempDir(), "script.awsl")
+	writeFile(t, script, "log.debug(\"checking\");\nlog.warn(\"slow\", {ms: 1500});\n")
+
+	tests := []struct {
+		name     string
+		options  []string
+		exitCode int
+		stderr   []string
+	}{
+		{"default", nil, exitOK, []string{"level=WARN msg=slow line=2 ms=1500\n"}},
+		{"debug level", []string{"--log-level=debug"}, exitOK, []string{"level=DEBUG msg=checking line=1\n"}},
+		{"separate value", []string{"--log-level", "error"}, exitOK, nil},
+		{"json", []string{"--log-format=json"}, exitOK, []string{`"level":"WARN","msg":"slow","line":2,"ms":1500}`, `"time":`}},
+		{"invalid level", []string{"--log-level=trace"}, exitUsage, []string{`invalid --log-level: "trace"`}},
+		{"invalid format", []string{"--log-format", "xml"}, exitUsage, []string{`invalid --log-format: "xml"`}},
+		{"missing value", []string{"--log-level"}, exitUsage, []string{"option --log-level requires a value"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			args := append([]string{"awsl"}, tt.options...)
+			if tt.name != "missing value" {
+				args = append(args, script)
+			}
+
+			var stdout, stderr bytes.Buffer
+			exitCode := run(args, nil, &stdout, &stderr)
+
+			if exitCode != tt.exitCode {
+				t.Errorf("expected exit code %d, got %d (stderr %q)", tt.exitCode, exitCode, stderr.String())
+			}
+			if tt.stderr == nil && stderr.Len() != 0 {
+				t.Errorf("expected no stderr, got %q", stderr.String())
+			}
+			for _, want := range tt.stderr {
+				if !strings.Contains(stderr.String(), want) {
+					t.Errorf("expected %q in stderr, got %q", want, stderr.String())
+				}
+			}
+		})
+	}
+}
+
+func TestRun_InlineAndStdin(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		stdin    string
+		exitCode int
+		stdout   string
+		stderr   string
+	}{
+		{"inline", []string{"awsl", "-e", `print("hi");`}, "", exitOK, "hi\n", ""},
+		{"inline result", []string{"awsl", "-e", "1 + 2;"}, "", exitOK, "3\n", ""},
+		{"inline with options and args", []string{"awsl", "--read-only", "-e", `print(args, flags.stage);`, "a", "--stage", "prod"}, "", exitOK, "[a] prod\n", ""},
+		{"inline shebang", []string{"awsl", "-e", "#!/usr/bin/env awsl\nprint(1);"}, "", exitOK, "1\n", ""},
+		{"inline parse error", []string{"awsl", "-e", "x = ;"}, "", exitParseError, "", "line 1, column 5"},
+		{"inline missing source", []string{"awsl", "-e"}, "", exitUsage, "", "option -e requires a value\n"},
+		{"stdin", []string{"awsl", "-"}, "x = 20;\nprint(x * 2);\n", exitOK, "40\n", ""},
+		{"stdin with args", []string{"awsl", "-", "in.csv", "--dry-run"}, `print(args[0], flags.dry_run);`, exitOK, "in.csv true\n", ""},
+		{"stdin runtime error", []string{"awsl", "-"}, "print(1);\n1 / 0;", exitRuntimeError, "1\n", "error at line 2, column 1: division by zero\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			exitCode := run(tt.args, strings.NewReader(tt.stdin), &stdout, &stderr)
+
+			if exitCode != tt.exitCode {
+				t.Errorf("expected exit code %d, got %d (stderr %q)", tt.exitCode, exitCode, stderr.String())
+			}
+			if stdout.String() != tt.stdout {
+				t.Errorf("wrong stdout. got=%q, want=%q", stdout.String(), tt.stdout)
+			}
+			if !strings.Contains(stderr.String(), tt.stderr) || (tt.stderr == "" && stderr.Len() != 0) {
+				t.Errorf("wrong stderr. got=%q, want=%q", stderr.String(), tt.stderr)
+			}
+		})
+	}
+}
+
+func TestRun_InlinePathsRelativeToWorkingDirectory(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "data.txt"), "hello")
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.Chdir(wd) })
+
+	var stdout, stderr bytes.Buffer
+	exitCode := run([]string{"awsl", "-e", `print(read_file("data.txt"));`}, nil, &stdout, &stderr)
+
+	if exitCode != exitOK || stdout.String() != "hello\n" {
+		t.Errorf("expected file read from working directory, got %d %q %q", exitCode, stdout.String(), stderr.String())
+	}
+}
+
 func TestRun_GoldenFiles(t *testing.T) {
 	testFiles, err := filepath.Glob("../../testdata/*.awsl")
 	if err != nil {
@@ -73,7 +315,7 @@ func TestRun_GoldenFiles(t *testing.T) {
 			goldenFile := strings.TrimSuffix(testFile, ".awsl") + ".golden"
 
 			var stdout, stderr bytes.Buffer
-			exitCode := run([]string{"awsl", testFile}, &stdout, &stderr)
+			exitCode := run([]string{"awsl", testFile}, nil, &stdout, &stderr)
 
 			// Combine stdout and stderr for comparison
 			// Format: exit code on first line, then output
@@ -109,6 +351,14 @@ func TestRun_GoldenFiles(t *testing.T) {
 	}
 }
 
+// writeFile creates a file with the given contents.
+func writeFile(t *testing.T, path, contents string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(contents), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
 // itoa converts an int to a string without importing strconv.
 func itoa(n int) string {
 	if n == 0 {
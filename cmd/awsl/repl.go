@@ -0,0 +1,327 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/chzyer/readline"
+
+	"github.com/boattime/awsl/internal/ast"
+	"github.com/boattime/awsl/internal/completion"
+	"github.com/boattime/awsl/internal/eval"
+	"github.com/boattime/awsl/internal/lexer"
+	"github.com/boattime/awsl/internal/parser"
+	"github.com/boattime/awsl/internal/token"
+)
+
+// Prompts shown before each line of REPL input; the continuation prompt
+// is shown while a statement is unfinished.
+const (
+	replPrompt             = "awsl> "
+	replContinuationPrompt = "...   "
+)
+
+// replHistoryFile is the name of the file in the home directory that
+// keeps the REPL's history between sessions.
+const replHistoryFile = ".awsl_history"
+
+// replHelp is printed by :help.
+const replHelp = `Enter statements to run them; the last semicolon may be left out.
+A statement with unclosed brackets continues on the next line.
+Commands:
+  :env        show the variables
+  :load FILE  run a script in this session; relative paths are
+              resolved against the directory awsl was started in
+  :help       show this help
+  :quit       leave the REPL (or press Ctrl-D)`
+
+// lineReader reads REPL input a line at a time. Readline returns io.EOF
+// at the end of the input and readline.ErrInterrupt on Ctrl-C.
+type lineReader interface {
+	Readline() (string, error)
+	SetPrompt(prompt string)
+	Close() error
+}
+
+// runREPL reads statements from stdin and evaluates them in one
+// environment until the input ends, :quit or exit(). On a terminal, input
+// is read with line editing, history and tab completion; otherwise lines
+// are read as they come, without prompts. It returns the code passed to
+// exit, or exitOK.
+func runREPL(cfg config, stdin io.Reader, stdout, stderr io.Writer) int {
+	dir, err := os.Getwd()
+	if err != nil {
+		fmt.Fprintf(stderr, "error resolving current directory: %v\n", err)
+		return exitRuntimeError
+	}
+	r := &repl{env: cfg.newEnvironment(dir, stdout, nil), dir: dir, stdout: stdout, stderr: stderr}
+
+	lines, interactive, err := newLineReader(stdin, stdout, stderr, replCompleter{env: r.env})
+	if err != nil {
+		fmt.Fprintf(stderr, "error starting REPL: %v\n", err)
+		return exitRuntimeError
+	}
+	defer lines.Close()
+
+	if interactive {
+		fmt.Fprintf(stdout, "awsl %s; type :help for help\n", Version)
+	}
+
+	for {
+		if len(r.pending) > 0 {
+			lines.SetPrompt(replContinuationPrompt)
+		} else {
+			lines.SetPrompt(replPrompt)
+		}
+
+		line, err := lines.Readline()
+		if errors.Is(err, readline.ErrInterrupt) {
+			// Ctrl-C abandons an unfinished statement
+			r.pending = nil
+			continue
+		}
+		if errors.Is(err, io.EOF) {
+			return exitOK
+		}
+		if err != nil {
+			fmt.Fprintf(stderr, "error reading input: %v\n", err)
+			return exitRuntimeError
+		}
+
+		if code, done := r.feed(line); done {
+			return code
+		}
+	}
+}
+
+// newLineReader returns a line reader with editing, history and tab
+// completion if stdin is a terminal, and a plain one otherwise.
+func newLineReader(stdin io.Reader, stdout, stderr io.Writer, completer readline.AutoCompleter) (lineReader, bool, error) {
+	file, ok := stdin.(*os.File)
+	if !ok || !readline.IsTerminal(int(file.Fd())) {
+		return &scannerReader{scanner: bufio.NewScanner(stdin)}, false, nil
+	}
+
+	historyFile := ""
+	if home, err := os.UserHomeDir(); err == nil {
+		historyFile = filepath.Join(home, replHistoryFile)
+	}
+	instance, err := readline.NewEx(&readline.Config{
+		Prompt:          replPrompt,
+		HistoryFile:     historyFile,
+		InterruptPrompt: "^C",
+		AutoComplete:    completer,
+		Stdin:           file,
+		Stdout:          stdout,
+		Stderr:          stderr,
+	})
+	if err != nil {
+		return nil, false, err
+	}
+	return instance, true, nil
+}
+
+// replCompleter completes names in REPL input from the session's
+// environment.
+type replCompleter struct {
+	env *eval.Environment
+}
+
+// Do implements readline.AutoCompleter. It returns the rest of each
+// candidate after the part of the word already typed before pos.
+func (c replCompleter) Do(line []rune, pos int) ([][]rune, int) {
+	text := string(line[:pos])
+	candidates, start := completion.Complete(c.env, text, len(text))
+	typed := text[start:]
+
+	suffixes := make([][]rune, len(candidates))
+	for i, candidate := range candidates {
+		suffixes[i] = []rune(strings.TrimPrefix(candidate, typed))
+	}
+	return suffixes, utf8.RuneCountInString(typed)
+}
+
+// scannerReader reads lines from input that is not a terminal, such as a
+// pipe, without prompts or editing.
+type scannerReader struct {
+	scanner *bufio.Scanner
+}
+
+// Readline returns the next line, or io.EOF at the end of the input.
+func (s *scannerReader) Readline() (string, error) {
+	if s.scanner.Scan() {
+		return s.scanner.Text(), nil
+	}
+	if err := s.scanner.Err(); err != nil {
+		return "", err
+	}
+	return "", io.EOF
+}
+
+// SetPrompt does nothing: prompts are only shown on a terminal.
+func (s *scannerReader) SetPrompt(string) {}
+
+// Close does nothing.
+func (s *scannerReader) Close() error { return nil }
+
+// repl holds the state of a REPL session: the environment that persists
+// between inputs, the directory that relative paths resolve against and
+// the lines of an unfinished statement.
+type repl struct {
+	env     *eval.Environment
+	dir     string
+	pending []string
+	stdout  io.Writer
+	stderr  io.Writer
+}
+
+// feed handles one line of input: a command, or source that is evaluated
+// once its brackets are closed. It returns true and an exit code when the
+// session should end.
+func (r *repl) feed(line string) (int, bool) {
+	if len(r.pending) == 0 {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" {
+			return exitOK, false
+		}
+		if strings.HasPrefix(trimmed, ":") {
+			return r.command(trimmed)
+		}
+	}
+
+	r.pending = append(r.pending, line)
+	source := strings.Join(r.pending, "\n")
+	if hasUnclosedBrackets(source) {
+		return exitOK, false
+	}
+	r.pending = nil
+
+	program, errs := parseREPLInput(source)
+	if len(errs) > 0 {
+		for _, parseErr := range errs {
+			fmt.Fprintln(r.stderr, parseErr)
+		}
+		return exitOK, false
+	}
+	return r.evaluate(program, true)
+}
+
+// command runs a REPL command such as :env.
+func (r *repl) command(line string) (int, bool) {
+	name, arg, _ := strings.Cut(line, " ")
+	arg = strings.TrimSpace(arg)
+
+	switch name {
+	case ":env":
+		r.showEnv()
+	case ":load":
+		if arg == "" {
+			fmt.Fprintln(r.stderr, "usage: :load FILE")
+			return exitOK, false
+		}
+		return r.load(arg)
+	case ":help":
+		fmt.Fprintln(r.stdout, replHelp)
+	case ":quit", ":exit":
+		return exitOK, true
+	default:
+		fmt.Fprintf(r.stderr, "unknown command: %s (type :help for help)\n", name)
+	}
+	return exitOK, false
+}
+
+// showEnv prints the session's variables and their values, sorted by
+// name. Builtins and namespaces are left out.
+func (r *repl) showEnv() {
+	for _, name := range r.env.Names() {
+		value, _ := r.env.Get(name)
+		switch value.(type) {
+		case *eval.Builtin, *eval.Namespace:
+			continue
+		}
+		fmt.Fprintf(r.stdout, "%s = %s\n", name, value.Inspect())
+	}
+}
+
+// load runs a script in the session's environment, so that the functions
+// and variables it defines can be used afterwards. A relative filename is
+// resolved against the session directory.
+func (r *repl) load(filename string) (int, bool) {
+	if !filepath.IsAbs(filename) {
+		filename = filepath.Join(r.dir, filename)
+	}
+	source, err := os.ReadFile(filename)
+	if err != nil {
+		fmt.Fprintf(r.stderr, "error reading file: %v\n", err)
+		return exitOK, false
+	}
+
+	p := parser.New(lexer.New(string(source)))
+	program := p.ParseProgram()
+	if p.HasErrors() {
+		for _, parseErr := range p.Errors() {
+			fmt.Fprintf(r.stderr, "%s: %s\n", filename, parseErr)
+		}
+		return exitOK, false
+	}
+	return r.evaluate(program, false)
+}
+
+// evaluate runs a program in the session's environment and reports an
+// error, or the result if show is set and it is not null. A call to exit
+// ends the session.
+func (r *repl) evaluate(program *ast.Program, show bool) (int, bool) {
+	result := eval.Eval(program, r.env)
+	if errObj, ok := result.(*eval.Error); ok {
+		if errObj.Category == eval.ExitRequest {
+			return errObj.ExitCode, true
+		}
+		fmt.Fprintln(r.stderr, errObj.Inspect())
+		return exitOK, false
+	}
+
+	if show && result != eval.NULL {
+		fmt.Fprintln(r.stdout, result.Inspect())
+	}
+	return exitOK, false
+}
+
+// parseREPLInput parses a complete REPL input. Input that only fails to
+// parse for want of a final semicolon is parsed with one added, so that
+// expressions can be typed as they are in a script's last line.
+func parseREPLInput(source string) (*ast.Program, []*parser.Error) {
+	p := parser.New(lexer.New(source))
+	program := p.ParseProgram()
+	if !p.HasErrors() {
+		return program, nil
+	}
+
+	withSemicolon := parser.New(lexer.New(source + ";"))
+	if fixed := withSemicolon.ParseProgram(); !withSemicolon.HasErrors() {
+		return fixed, nil
+	}
+	return program, p.Errors()
+}
+
+// hasUnclosedBrackets reports whether source opens more parentheses,
+// brackets or braces than it closes, so a statement continues on the
+// next line. Brackets inside strings and comments are not counted.
+func hasUnclosedBrackets(source string) bool {
+	depth := 0
+	l := lexer.New(source)
+	for tok := l.NextToken(); tok.Type != token.EOF; tok = l.NextToken() {
+		switch tok.Type {
+		case token.LPAREN, token.LBRACKET, token.LBRACE:
+			depth++
+		case token.RPAREN, token.RBRACKET, token.RBRACE:
+			depth--
+		}
+	}
+	return depth > 0
+}
@@ -0,0 +1,168 @@
+package main
+
+import (
+	"bytes"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/boattime/awsl/internal/eval"
+)
+
+// testREPL runs a REPL session on input and returns its exit code and
+// output.
+func testREPL(t *testing.T, input string) (int, string, string) {
+	t.Helper()
+	var stdout, stderr bytes.Buffer
+	cfg := config{logger: eval.NewLogger(&stderr, slog.LevelInfo, false)}
+	exitCode := runREPL(cfg, strings.NewReader(input), &stdout, &stderr)
+	return exitCode, stdout.String(), stderr.String()
+}
+
+func TestREPL(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		exitCode int
+		stdout   string
+		stderr   string
+	}{
+		{"expression", "1 + 2;\n", exitOK, "3\n", ""},
+		{"semicolon left out", "upper(\"abc\")\n", exitOK, "ABC\n", ""},
+		{"null not shown", "print(\"hi\")\n", exitOK, "hi\n", ""},
+		{"environment kept", "x = 20\nx * 2\n", exitOK, "40\n", ""},
+		{"multi-line function", "fn double(n) {\n  return n * 2;\n}\ndouble(21)\n", exitOK, "42\n", ""},
+		{"multi-line object", "user = {\n  name: \"ada\",\n  langs: [\n    \"go\"\n  ]\n}\nuser.langs\n", exitOK, "[go]\n", ""},
+		{"brackets in strings", "s = \"({[\"\nlen(s)\n", exitOK, "3\n", ""},
+		{"blank lines", "\n\n7\n", exitOK, "7\n", ""},
+		{"parse error continues", "x = ;\n5\n", exitOK, "5\n", "line 1, column 5"},
+		{"runtime error continues", "1 / 0\n6\n", exitOK, "6\n", "error at line 1, column 1: division by zero\n"},
+		{"exit", "exit(3)\nprint(\"not reached\")\n", 3, "", ""},
+		{"quit", ":quit\nprint(\"not reached\")\n", exitOK, "", ""},
+		{"help", ":help\n", exitOK, replHelp + "\n", ""},
+		{"unknown command", ":bogus\n", exitOK, "", "unknown command: :bogus (type :help for help)\n"},
+		{"load without file", ":load\n", exitOK, "", "usage: :load FILE\n"},
+		{"load missing file", ":load missing.awsl\n", exitOK, "", "error reading file:"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			exitCode, stdout, stderr := testREPL(t, tt.input)
+
+			if exitCode != tt.exitCode {
+				t.Errorf("expected exit code %d, got %d", tt.exitCode, exitCode)
+			}
+			if stdout != tt.stdout {
+				t.Errorf("wrong stdout. got=%q, want=%q", stdout, tt.stdout)
+			}
+			if !strings.Contains(stderr, tt.stderr) || (tt.stderr == "" && stderr != "") {
+				t.Errorf("wrong stderr. got=%q, want=%q", stderr, tt.stderr)
+			}
+		})
+	}
+}
+
+func TestREPL_Env(t *testing.T) {
+	_, stdout, _ := testREPL(t, "name = \"ada\"\ncount = 2\nitems = [1, {a: 1}]\n:env\n")
+
+	expected := "args = []\ncount = 2\nflags = {}\nitems = [1, {a: 1}]\nname = ada\n"
+	if stdout != expected {
+		t.Errorf("wrong :env output. got=%q, want=%q", stdout, expected)
+	}
+}
+
+func TestREPL_Load(t *testing.T) {
+	script := filepath.Join(t.TempDir(), "helpers.awsl")
+	writeFile(t, script, "fn greet(name) { return \"hello \" + name; }\nprint(\"loaded\");\n")
+
+	exitCode, stdout, stderr := testREPL(t, ":load "+script+"\ngreet(\"ada\")\n")
+
+	if exitCode != exitOK {
+		t.Errorf("expected exit code %d, got %d", exitOK, exitCode)
+	}
+	if stdout != "loaded\nhello ada\n" {
+		t.Errorf("wrong stdout. got=%q, stderr=%q", stdout, stderr)
+	}
+}
+
+func TestREPL_LoadRelativePath(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "helpers.awsl"), "x = 42;\n")
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.Chdir(wd) })
+
+	exitCode, stdout, stderr := testREPL(t, ":load helpers.awsl\nx\n")
+
+	if exitCode != exitOK || stdout != "42\n" {
+		t.Errorf("expected script loaded from the session directory, got %d %q %q", exitCode, stdout, stderr)
+	}
+}
+
+func TestREPLCompleter(t *testing.T) {
+	var stdout bytes.Buffer
+	env := eval.NewEnvironment(&stdout)
+	eval.RegisterBuiltins(env)
+	env.Set("user", eval.NewHash(
+		eval.HashPair{Key: "name", Value: &eval.String{Value: "ada"}},
+		eval.HashPair{Key: "nickname", Value: &eval.String{Value: "a"}},
+	))
+	completer := replCompleter{env: env}
+
+	tests := []struct {
+		line     string
+		pos      int
+		suffixes []string
+		length   int
+	}{
+		{"print(user.n", 12, []string{"ame", "ickname"}, 1},
+		{"user.name", 7, []string{"me"}, 2},
+		{"x = \"é\"; upp", 12, []string{"er"}, 3},
+		{"user.", 5, []string{"name", "nickname"}, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.line, func(t *testing.T) {
+			suffixes, length := completer.Do([]rune(tt.line), tt.pos)
+			got := make([]string, len(suffixes))
+			for i, suffix := range suffixes {
+				got[i] = string(suffix)
+			}
+			if strings.Join(got, ",") != strings.Join(tt.suffixes, ",") {
+				t.Errorf("wrong suffixes. got=%q, want=%q", got, tt.suffixes)
+			}
+			if length != tt.length {
+				t.Errorf("wrong length. got=%d, want=%d", length, tt.length)
+			}
+		})
+	}
+}
+
+func TestHasUnclosedBrackets(t *testing.T) {
+	tests := []struct {
+		source   string
+		expected bool
+	}{
+		{"x = 1;", false},
+		{"fn f() {", true},
+		{"fn f() {\n}", false},
+		{"f(1,", true},
+		{"[1, [2]", true},
+		{"x = \"{\";", false},
+		{"x = 1; // {", false},
+		{"}", false},
+	}
+
+	for _, tt := range tests {
+		if got := hasUnclosedBrackets(tt.source); got != tt.expected {
+			t.Errorf("hasUnclosedBrackets(%q) = %v, want %v", tt.source, got, tt.expected)
+		}
+	}
+}
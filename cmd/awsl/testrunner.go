@@ -0,0 +1,170 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/boattime/awsl/internal/ast"
+	"github.com/boattime/awsl/internal/eval"
+	"github.com/boattime/awsl/internal/lexer"
+	"github.com/boattime/awsl/internal/parser"
+)
+
+// testFileSuffix marks the scripts that awsl test searches directories for.
+const testFileSuffix = "_test.awsl"
+
+// testFunctionPrefix marks the functions in a test file that are tests.
+const testFunctionPrefix = "test_"
+
+// runTests runs the tests in paths, or in the current directory if paths
+// is empty. Directories are searched recursively for files ending in
+// _test.awsl; files given by name are run whatever their name. Every
+// top-level function whose name starts with test_ is a test, run in a
+// fresh environment: the file is evaluated, then the function is called
+// without arguments. A test fails if the file or the test stops with an
+// error, such as a failed assert; exit(0) in a test passes it, but in the
+// file it fails every test. It returns exitRuntimeError if any test
+// failed.
+func runTests(paths []string, cfg config, stdout, stderr io.Writer) int {
+	if len(paths) == 0 {
+		paths = []string{"."}
+	}
+
+	files, err := findTestFiles(paths)
+	if err != nil {
+		fmt.Fprintf(stderr, "error finding tests: %v\n", err)
+		return exitUsage
+	}
+
+	passed, failed := 0, 0
+	for _, filename := range files {
+		p, f := runTestFile(filename, cfg, stdout)
+		passed += p
+		failed += f
+	}
+
+	if passed+failed == 0 {
+		fmt.Fprintln(stdout, "no tests found")
+		return exitOK
+	}
+	fmt.Fprintf(stdout, "%d passed, %d failed\n", passed, failed)
+	if failed > 0 {
+		return exitRuntimeError
+	}
+	return exitOK
+}
+
+// findTestFiles returns the test files in paths, in lexical order within
+// each directory.
+func findTestFiles(paths []string) ([]string, error) {
+	var files []string
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, err
+		}
+		if !info.IsDir() {
+			files = append(files, path)
+			continue
+		}
+
+		err = filepath.WalkDir(path, func(name string, entry fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if !entry.IsDir() && strings.HasSuffix(name, testFileSuffix) {
+				files = append(files, name)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return files, nil
+}
+
+// runTestFile runs the tests in one file and reports each on stdout.
+// A file that cannot be read or parsed counts as one failed test.
+// Returns the number of tests that passed and failed.
+func runTestFile(filename string, cfg config, stdout io.Writer) (int, int) {
+	source, err := os.ReadFile(filename)
+	if err != nil {
+		fmt.Fprintf(stdout, "--- FAIL: %s\n    error reading file: %v\n", filename, err)
+		return 0, 1
+	}
+
+	p := parser.New(lexer.New(string(source)))
+	program := p.ParseProgram()
+	if p.HasErrors() {
+		fmt.Fprintf(stdout, "--- FAIL: %s\n", filename)
+		for _, parseErr := range p.Errors() {
+			fmt.Fprintf(stdout, "    %s\n", parseErr)
+		}
+		return 0, 1
+	}
+
+	dir, err := filepath.Abs(filepath.Dir(filename))
+	if err != nil {
+		fmt.Fprintf(stdout, "--- FAIL: %s\n    error resolving script directory: %v\n", filename, err)
+		return 0, 1
+	}
+
+	passed, failed := 0, 0
+	for _, test := range testFunctions(program) {
+		errObj, message := runTest(program, test, cfg.newEnvironment(dir, stdout, nil))
+		if errObj != nil {
+			fmt.Fprintf(stdout, "--- FAIL: %s (%s)\n", test.Name.Value, filename)
+			fmt.Fprintf(stdout, "    %s:%d:%d: %s\n", filename, errObj.Line, errObj.Column, message)
+			failed++
+			continue
+		}
+		fmt.Fprintf(stdout, "--- PASS: %s (%s)\n", test.Name.Value, filename)
+		passed++
+	}
+	return passed, failed
+}
+
+// runTest evaluates the file in env and calls the test function. It
+// returns the error that failed the test and the message to report, or
+// nil if the test passed. Any error while evaluating the file fails the
+// test, even a call to exit(0), since the test function is never called;
+// a call to exit(0) from the test itself passes.
+func runTest(program *ast.Program, test *ast.FunctionDeclaration, env *eval.Environment) (*eval.Error, string) {
+	if errObj, ok := eval.Eval(program, env).(*eval.Error); ok {
+		if errObj.Category == eval.ExitRequest {
+			return errObj, fmt.Sprintf("file called exit(%d) before the test ran", errObj.ExitCode)
+		}
+		return errObj, errObj.Message
+	}
+
+	call := &ast.CallExpression{Token: test.Name.Token, Function: test.Name}
+	errObj, ok := eval.Eval(call, env).(*eval.Error)
+	switch {
+	case !ok:
+		return nil, ""
+	case errObj.Category == eval.ExitRequest && errObj.ExitCode == 0:
+		return nil, ""
+	case errObj.Category == eval.ExitRequest:
+		return errObj, fmt.Sprintf("test called exit(%d)", errObj.ExitCode)
+	default:
+		return errObj, errObj.Message
+	}
+}
+
+// testFunctions returns the top-level function declarations that are
+// tests, in source order.
+func testFunctions(program *ast.Program) []*ast.FunctionDeclaration {
+	var tests []*ast.FunctionDeclaration
+	for _, stmt := range program.Statements {
+		decl, ok := stmt.(*ast.FunctionDeclaration)
+		if ok && strings.HasPrefix(decl.Name.Value, testFunctionPrefix) {
+			tests = append(tests, decl)
+		}
+	}
+	return tests
+}
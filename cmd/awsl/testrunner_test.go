@@ -0,0 +1,163 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRunTests(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "math_test.awsl"), `fn add(a, b) { return a + b; }
+
+fn test_add() {
+    assert add(1, 2) == 3, "small numbers";
+}
+
+fn test_add_wrong() {
+    assert add(2, 2) == 5, "two and two";
+}
+
+fn helper() {
+    assert false;
+}
+`)
+	if err := os.Mkdir(filepath.Join(dir, "nested"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	writeFile(t, filepath.Join(dir, "nested", "text_test.awsl"), `fn test_len() { assert len("ab") == 2; }
+fn test_error() { x = 1 / 0; }
+`)
+	writeFile(t, filepath.Join(dir, "lib.awsl"), `fn test_not_run() { assert false; }`)
+
+	var stdout, stderr bytes.Buffer
+	exitCode := run([]string{"awsl", "test", dir}, nil, &stdout, &stderr)
+
+	if exitCode != exitRuntimeError {
+		t.Errorf("expected exit code %d, got %d", exitRuntimeError, exitCode)
+	}
+	if stderr.Len() != 0 {
+		t.Errorf("expected no stderr, got %q", stderr.String())
+	}
+
+	mathFile := filepath.Join(dir, "math_test.awsl")
+	textFile := filepath.Join(dir, "nested", "text_test.awsl")
+	expected := "--- PASS: test_add (" + mathFile + ")\n" +
+		"--- FAIL: test_add_wrong (" + mathFile + ")\n" +
+		"    " + mathFile + ":8:5: assertion failed: (add(2, 2) == 5): two and two\n" +
+		"--- PASS: test_len (" + textFile + ")\n" +
+		"--- FAIL: test_error (" + textFile + ")\n" +
+		"    " + textFile + ":2:23: division by zero\n" +
+		"2 passed, 2 failed\n"
+	if stdout.String() != expected {
+		t.Errorf("wrong output.\ngot:\n%s\nwant:\n%s", stdout.String(), expected)
+	}
+}
+
+func TestRunTests_FreshEnvironment(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "setup_test.awsl")
+	writeFile(t, file, `print("setup");
+fn test_first() { assert true; }
+fn test_second() { assert true; }
+`)
+
+	var stdout, stderr bytes.Buffer
+	exitCode := run([]string{"awsl", "test", file}, nil, &stdout, &stderr)
+
+	if exitCode != exitOK {
+		t.Errorf("expected exit code %d, got %d", exitOK, exitCode)
+	}
+	expected := "setup\n--- PASS: test_first (" + file + ")\n" +
+		"setup\n--- PASS: test_second (" + file + ")\n" +
+		"2 passed, 0 failed\n"
+	if stdout.String() != expected {
+		t.Errorf("wrong output.\ngot:\n%s\nwant:\n%s", stdout.String(), expected)
+	}
+}
+
+func TestRunTests_Exit(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "exit_test.awsl")
+	writeFile(t, file, `fn test_exit_zero() { exit(0); assert false; }
+fn test_exit_code() { exit(3); }
+fn test_after_exit() { assert true; }
+`)
+
+	var stdout, stderr bytes.Buffer
+	exitCode := run([]string{"awsl", "test", file}, nil, &stdout, &stderr)
+
+	if exitCode != exitRuntimeError {
+		t.Errorf("expected exit code %d, got %d", exitRuntimeError, exitCode)
+	}
+	expected := "--- PASS: test_exit_zero (" + file + ")\n" +
+		"--- FAIL: test_exit_code (" + file + ")\n" +
+		"    " + file + ":2:23: test called exit(3)\n" +
+		"--- PASS: test_after_exit (" + file + ")\n" +
+		"2 passed, 1 failed\n"
+	if stdout.String() != expected {
+		t.Errorf("wrong output.\ngot:\n%s\nwant:\n%s", stdout.String(), expected)
+	}
+}
+
+func TestRunTests_ExitWhileLoading(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "exit_test.awsl")
+	writeFile(t, file, `exit(0);
+fn test_broken() { assert 1 == 2, "should fail"; }
+fn test_fine() { assert true; }
+`)
+
+	var stdout, stderr bytes.Buffer
+	exitCode := run([]string{"awsl", "test", file}, nil, &stdout, &stderr)
+
+	if exitCode != exitRuntimeError {
+		t.Errorf("expected exit code %d, got %d", exitRuntimeError, exitCode)
+	}
+	expected := "--- FAIL: test_broken (" + file + ")\n" +
+		"    " + file + ":1:1: file called exit(0) before the test ran\n" +
+		"--- FAIL: test_fine (" + file + ")\n" +
+		"    " + file + ":1:1: file called exit(0) before the test ran\n" +
+		"0 passed, 2 failed\n"
+	if stdout.String() != expected {
+		t.Errorf("wrong output.\ngot:\n%s\nwant:\n%s", stdout.String(), expected)
+	}
+}
+
+func TestRunTests_Errors(t *testing.T) {
+	dir := t.TempDir()
+	broken := filepath.Join(dir, "broken_test.awsl")
+	writeFile(t, broken, "fn test_x() { assert ; }\n")
+	empty := t.TempDir()
+
+	tests := []struct {
+		name     string
+		args     []string
+		exitCode int
+		stdout   string
+		stderr   string
+	}{
+		{"parse error", []string{"awsl", "test", broken}, exitRuntimeError, "--- FAIL: " + broken + "\n    line 1", ""},
+		{"no tests", []string{"awsl", "test", empty}, exitOK, "no tests found\n", ""},
+		{"missing path", []string{"awsl", "test", filepath.Join(dir, "missing")}, exitUsage, "", "error finding tests:"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			exitCode := run(tt.args, nil, &stdout, &stderr)
+
+			if exitCode != tt.exitCode {
+				t.Errorf("expected exit code %d, got %d", tt.exitCode, exitCode)
+			}
+			if !strings.HasPrefix(stdout.String(), tt.stdout) {
+				t.Errorf("expected stdout to start with %q, got %q", tt.stdout, stdout.String())
+			}
+			if !strings.Contains(stderr.String(), tt.stderr) {
+				t.Errorf("expected %q in stderr, got %q", tt.stderr, stderr.String())
+			}
+		})
+	}
+}
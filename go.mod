@@ -1,3 +1,11 @@
 module github.com/boattime/awsl
 
 go 1.22.3
+
+require (
+	github.com/BurntSushi/toml v1.4.0
+	github.com/chzyer/readline v1.5.1
+	gopkg.in/yaml.v3 v3.0.1
+)
+
+require golang.org/x/sys v0.0.0-20220310020820-b874c991c1a5 // indirect
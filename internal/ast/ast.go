@@ -4,6 +4,7 @@ package ast
 
 import (
 	"strings"
+	"time"
 
 	"github.com/boattime/awsl/internal/token"
 )
@@ -248,6 +249,35 @@ func (rs *ReturnStatement) String() string {
 	return out.String()
 }
 
+// AssertStatement represents an assertion, which fails unless its
+// condition is truthy.
+// Example: assert total > 0, "no items";
+type AssertStatement struct {
+	Token     token.Token // The 'assert' token
+	Condition Expression
+	Message   Expression // May be nil
+}
+
+func (as *AssertStatement) statementNode() {}
+
+// Pos returns the position of the assert keyword.
+func (as *AssertStatement) Pos() Position {
+	return Position{Line: as.Token.Line, Column: as.Token.Column}
+}
+
+// String returns the assert statement as a string.
+func (as *AssertStatement) String() string {
+	var out strings.Builder
+	out.WriteString("assert ")
+	out.WriteString(as.Condition.String())
+	if as.Message != nil {
+		out.WriteString(", ")
+		out.WriteString(as.Message.String())
+	}
+	out.WriteString(";")
+	return out.String()
+}
+
 // FunctionDeclaration represents a function definition.
 // Example: fn name(param1, param2) { ... }
 type FunctionDeclaration struct {
@@ -255,6 +285,7 @@ type FunctionDeclaration struct {
 	Name       *Identifier
 	Parameters []*Identifier
 	Body       *BlockStatement
+	Doc        string // Text of the /// comments directly above, or ""
 }
 
 func (fd *FunctionDeclaration) statementNode() {}
@@ -334,6 +365,25 @@ func (fl *FloatLiteral) String() string {
 	return fl.Token.Literal
 }
 
+// DurationLiteral represents a length of time.
+// Examples: 30 days, 5 min, 2h30m
+type DurationLiteral struct {
+	Token token.Token
+	Value time.Duration
+}
+
+func (dl *DurationLiteral) expressionNode() {}
+
+// Pos returns the position of the duration.
+func (dl *DurationLiteral) Pos() Position {
+	return Position{Line: dl.Token.Line, Column: dl.Token.Column}
+}
+
+// String returns the duration as written in the source.
+func (dl *DurationLiteral) String() string {
+	return dl.Token.Literal
+}
+
 // StringLiteral represents a string value.
 type StringLiteral struct {
 	Token token.Token
@@ -650,3 +700,74 @@ func (ge *GroupedExpression) String() string {
 	out.WriteString(")")
 	return out.String()
 }
+
+// ListComprehension represents a list comprehension.
+// Example: [x.name for x in items if x.active]
+type ListComprehension struct {
+	Token     token.Token // The '[' token
+	Element   Expression  // The expression producing each element
+	Iterator  *Identifier // The comprehension variable
+	Iterable  Expression  // The collection being iterated
+	Condition Expression  // May be nil if no if clause
+}
+
+func (lc *ListComprehension) expressionNode() {}
+
+// Pos returns the position of the opening bracket.
+func (lc *ListComprehension) Pos() Position {
+	return Position{Line: lc.Token.Line, Column: lc.Token.Column}
+}
+
+// String returns the list comprehension as a string.
+func (lc *ListComprehension) String() string {
+	var out strings.Builder
+	out.WriteString("[")
+	out.WriteString(lc.Element.String())
+	out.WriteString(" for ")
+	out.WriteString(lc.Iterator.String())
+	out.WriteString(" in ")
+	out.WriteString(lc.Iterable.String())
+	if lc.Condition != nil {
+		out.WriteString(" if ")
+		out.WriteString(lc.Condition.String())
+	}
+	out.WriteString("]")
+	return out.String()
+}
+
+// HashComprehension represents an object comprehension.
+// Example: {x.pk: x for x in items}
+type HashComprehension struct {
+	Token     token.Token // The '{' token
+	Key       Expression  // The expression producing each key
+	Value     Expression  // The expression producing each value
+	Iterator  *Identifier // The comprehension variable
+	Iterable  Expression  // The collection being iterated
+	Condition Expression  // May be nil if no if clause
+}
+
+func (hc *HashComprehension) expressionNode() {}
+
+// Pos returns the position of the opening brace.
+func (hc *HashComprehension) Pos() Position {
+	return Position{Line: hc.Token.Line, Column: hc.Token.Column}
+}
+
+// String returns the hash comprehension as a string.
+func (hc *HashComprehension) String() string {
+	var out strings.Builder
+	out.WriteString("{")
+	out.WriteString(hc.Key.String())
+	out.WriteString(": ")
+	out.WriteString(hc.Value.String())
+	out.WriteString(" for ")
+	out.WriteString(hc.Iterator.String())
+	out.WriteString(" in ")
+	out.WriteString(hc.Iterable.String())
+	if hc.Condition != nil {
+		out.WriteString(" if ")
+		out.WriteString(hc.Condition.String())
+	}
+	out.WriteString("}")
+	return out.String()
+}
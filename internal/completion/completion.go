@@ -0,0 +1,231 @@
+// Package completion suggests names for partly typed AWSL source. It is
+// shared by the REPL and editor integrations: given the source, the
+// cursor offset and an environment, it returns the identifiers, builtins,
+// namespace members and object keys that could be typed there.
+package completion
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/boattime/awsl/internal/eval"
+)
+
+// Complete returns the names that could complete the word ending at
+// offset in source, sorted, and the offset where that word starts; each
+// candidate replaces source[start:offset].
+//
+// After a dot, such as in item.na or dynamo., the candidates are the keys
+// of the object or the members of the namespace that the chain of names
+// before the dot refers to in env. Otherwise they are the names bound in
+// env and its outer scopes, and the builtin functions and namespaces.
+// env may be nil, for example in an editor that has not run the script,
+// in which case only builtins are suggested. Inside a string or comment
+// there are no candidates.
+func Complete(env *eval.Environment, source string, offset int) ([]string, int) {
+	if offset < 0 || offset > len(source) {
+		return nil, offset
+	}
+	text := source[:offset]
+	if inStringOrComment(text) {
+		return nil, offset
+	}
+
+	start := wordStart(text)
+	prefix := text[start:]
+	if start > 0 && text[start-1] == '.' {
+		path, ok := memberPath(text[:start-1])
+		if !ok {
+			return nil, start
+		}
+		return matching(members(env, path), prefix), start
+	}
+	if startsWithDigit(prefix) {
+		return nil, start
+	}
+	return matching(globals(env), prefix), start
+}
+
+// globals returns the names in scope: those bound in env, and the
+// builtins.
+func globals(env *eval.Environment) []string {
+	var names []string
+	if env != nil {
+		names = env.Names()
+	}
+	for name := range eval.Builtins {
+		names = append(names, name)
+	}
+	for name := range eval.Namespaces {
+		names = append(names, name)
+	}
+	return names
+}
+
+// members returns the names that can follow a dot after the value that
+// path refers to: the keys of an object or the members of a namespace.
+// Keys that are not identifiers are left out, since they cannot be
+// written after a dot.
+func members(env *eval.Environment, path []string) []string {
+	value, ok := lookup(env, path[0])
+	for _, name := range path[1:] {
+		if !ok {
+			break
+		}
+		value, ok = member(value, name)
+	}
+	if !ok {
+		return nil
+	}
+
+	var names []string
+	switch value := value.(type) {
+	case *eval.Hash:
+		for _, key := range value.Keys() {
+			if isIdentifier(key) {
+				names = append(names, key)
+			}
+		}
+	case *eval.Namespace:
+		for name := range value.Members {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// lookup returns the value of a name in env, falling back to the builtin
+// namespaces when env is nil or does not bind it.
+func lookup(env *eval.Environment, name string) (eval.Object, bool) {
+	if env != nil {
+		if value, ok := env.Get(name); ok {
+			return value, true
+		}
+	}
+	if namespace, ok := eval.Namespaces[name]; ok {
+		return namespace, true
+	}
+	return nil, false
+}
+
+// member returns the value of obj.name for objects and namespaces.
+func member(obj eval.Object, name string) (eval.Object, bool) {
+	switch obj := obj.(type) {
+	case *eval.Hash:
+		return obj.Get(name)
+	case *eval.Namespace:
+		value, ok := obj.Members[name]
+		return value, ok
+	}
+	return nil, false
+}
+
+// matching returns the names that start with prefix, sorted and without
+// duplicates.
+func matching(names []string, prefix string) []string {
+	var result []string
+	for _, name := range names {
+		if strings.HasPrefix(name, prefix) {
+			result = append(result, name)
+		}
+	}
+	slices.Sort(result)
+	return slices.Compact(result)
+}
+
+// memberPath returns the names of a chain such as a.b.c that ends text,
+// as in a.b.c.<cursor>.
+func memberPath(text string) ([]string, bool) {
+	var path []string
+	for {
+		start := wordStart(text)
+		name := text[start:]
+		if !isIdentifier(name) {
+			return nil, false
+		}
+		path = append([]string{name}, path...)
+		if start == 0 || text[start-1] != '.' {
+			return path, true
+		}
+		text = text[:start-1]
+	}
+}
+
+// wordStart returns the offset of the identifier characters that end
+// text.
+func wordStart(text string) int {
+	start := len(text)
+	for start > 0 && isIdentifierChar(text[start-1]) {
+		start--
+	}
+	return start
+}
+
+// inStringOrComment reports whether the end of text is inside a string
+// literal, a // comment or a /* */ comment, which nest as in the lexer.
+func inStringOrComment(text string) bool {
+	inString := false
+	for i := 0; i < len(text); i++ {
+		switch {
+		case text[i] == '"':
+			inString = !inString
+		case !inString && strings.HasPrefix(text[i:], "//"):
+			end := strings.IndexByte(text[i:], '\n')
+			if end < 0 {
+				return true
+			}
+			i += end
+		case !inString && strings.HasPrefix(text[i:], "/*"):
+			end := blockCommentEnd(text[i:])
+			if end < 0 {
+				return true
+			}
+			i += end - 1
+		}
+	}
+	return inString
+}
+
+// blockCommentEnd returns the offset just past the */ that closes the
+// block comment at the start of text, or -1 if it is not closed.
+func blockCommentEnd(text string) int {
+	depth := 0
+	for i := 0; i+1 < len(text); i++ {
+		switch text[i : i+2] {
+		case "/*":
+			depth++
+			i++
+		case "*/":
+			depth--
+			i++
+			if depth == 0 {
+				return i + 1
+			}
+		}
+	}
+	return -1
+}
+
+// isIdentifier reports whether name can be written as an identifier.
+func isIdentifier(name string) bool {
+	if name == "" || startsWithDigit(name) {
+		return false
+	}
+	for i := 0; i < len(name); i++ {
+		if !isIdentifierChar(name[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// startsWithDigit reports whether text starts with a digit, as a number
+// does.
+func startsWithDigit(text string) bool {
+	return text != "" && text[0] >= '0' && text[0] <= '9'
+}
+
+// isIdentifierChar reports whether c can appear in an identifier.
+func isIdentifierChar(c byte) bool {
+	return c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9'
+}
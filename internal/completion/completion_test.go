@@ -0,0 +1,124 @@
+package completion
+
+import (
+	"bytes"
+	"slices"
+	"testing"
+
+	"github.com/boattime/awsl/internal/eval"
+	"github.com/boattime/awsl/internal/lexer"
+	"github.com/boattime/awsl/internal/parser"
+)
+
+// testEnvironment returns an environment with the builtins registered in
+// which input has been evaluated.
+func testEnvironment(t *testing.T, input string) *eval.Environment {
+	t.Helper()
+	p := parser.New(lexer.New(input))
+	program := p.ParseProgram()
+	if p.HasErrors() {
+		t.Fatalf("parse errors: %v", p.Errors())
+	}
+	var stdout bytes.Buffer
+	env := eval.NewEnvironment(&stdout)
+	eval.RegisterBuiltins(env)
+	if result := eval.Eval(program, env); result.Type() == eval.ERROR_OBJ {
+		t.Fatalf("eval error: %s", result.Inspect())
+	}
+	return env
+}
+
+func TestComplete(t *testing.T) {
+	env := testEnvironment(t, `
+item = {name: "ada", nickname: "a", meta: set_path({created: 1}, "not ident", 2)};
+items = [item];
+fn item_count(xs) { return len(xs); }
+`)
+
+	tests := []struct {
+		source     string
+		candidates []string
+		start      int
+	}{
+		{"ite", []string{"item", "item_count", "items"}, 0},
+		{"x = len(ite", []string{"item", "item_count", "items"}, 8},
+		{"print(item.n", []string{"name", "nickname"}, 11},
+		{"item.", []string{"meta", "name", "nickname"}, 5},
+		{"item.meta.", []string{"created"}, 10},
+		{"item.meta.cr", []string{"created"}, 10},
+		{"json.p", []string{"parse"}, 5},
+		{"log.", []string{"debug", "error", "info", "warn"}, 4},
+		{"sort_", []string{"sort_by"}, 0},
+		{"items.", nil, 6},
+		{"item.missing.", nil, 13},
+		{"unknown.", nil, 8},
+		{"f().", nil, 4},
+		{"zzz", nil, 0},
+		{"x = 12", nil, 4},
+		{`print("ite`, nil, 10},
+		{`x = "a"; ite`, []string{"item", "item_count", "items"}, 9},
+		{"// ite", nil, 6},
+		{"x = 1; // c\nite", []string{"item", "item_count", "items"}, 12},
+		{"/* item.", nil, 8},
+		{"/* a /* b */ item.", nil, 18},
+		{"/* a */ ite", []string{"item", "item_count", "items"}, 8},
+		{"/* a /* b */ c */ ite", []string{"item", "item_count", "items"}, 18},
+		{`/* "x */ ite`, []string{"item", "item_count", "items"}, 9},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.source, func(t *testing.T) {
+			candidates, start := Complete(env, tt.source, len(tt.source))
+			if !slices.Equal(candidates, tt.candidates) {
+				t.Errorf("wrong candidates. got=%v, want=%v", candidates, tt.candidates)
+			}
+			if start != tt.start {
+				t.Errorf("wrong start. got=%d, want=%d", start, tt.start)
+			}
+		})
+	}
+}
+
+func TestCompleteMidLine(t *testing.T) {
+	env := testEnvironment(t, `item = {name: "ada"};`)
+
+	source := "print(item.na);"
+	candidates, start := Complete(env, source, len("print(item.na"))
+	if !slices.Equal(candidates, []string{"name"}) || start != 11 {
+		t.Errorf("wrong completion. got=%v at %d", candidates, start)
+	}
+}
+
+func TestCompleteWithoutEnvironment(t *testing.T) {
+	candidates, _ := Complete(nil, "uppe", 4)
+	if !slices.Equal(candidates, []string{"upper"}) {
+		t.Errorf("wrong builtin candidates. got=%v", candidates)
+	}
+
+	candidates, _ = Complete(nil, "csv.", 4)
+	if !slices.Contains(candidates, "parse") {
+		t.Errorf("expected namespace members, got %v", candidates)
+	}
+
+	candidates, _ = Complete(nil, "item.", 5)
+	if candidates != nil {
+		t.Errorf("expected no candidates, got %v", candidates)
+	}
+}
+
+func TestCompleteEmpty(t *testing.T) {
+	env := testEnvironment(t, `item = 1;`)
+
+	candidates, start := Complete(env, "", 0)
+	if !slices.Contains(candidates, "item") || !slices.Contains(candidates, "print") || start != 0 {
+		t.Errorf("expected every name, got %v at %d", candidates, start)
+	}
+	if len(candidates) != len(slices.Compact(slices.Clone(candidates))) {
+		t.Errorf("expected no duplicates, got %v", candidates)
+	}
+
+	candidates, _ = Complete(env, "item", 10)
+	if candidates != nil {
+		t.Errorf("expected no candidates past the end, got %v", candidates)
+	}
+}
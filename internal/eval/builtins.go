@@ -3,7 +3,11 @@ package eval
 
 import (
 	"fmt"
+	"math"
+	"strconv"
+	"strings"
 	"time"
+	"unicode/utf8"
 )
 
 // Builtins contains all built-in functions available in AWSL.
@@ -16,13 +20,500 @@ var Builtins = map[string]*Builtin{
 		Name: "clock",
 		Fn:   builtinClock,
 	},
+	"now": {
+		Name: "now",
+		Fn:   builtinNow,
+	},
+	"parse_time": {
+		Name:   "parse_time",
+		Fn:     builtinParseTime,
+		Params: []string{"string", "layout", "zone"},
+	},
+	"format_time": {
+		Name:   "format_time",
+		Fn:     builtinFormatTime,
+		Params: []string{"time", "layout"},
+	},
+	"in_zone": {
+		Name:   "in_zone",
+		Fn:     builtinInZone,
+		Params: []string{"time", "zone"},
+	},
+	"from_unix": {
+		Name:   "from_unix",
+		Fn:     builtinFromUnix,
+		Params: []string{"seconds"},
+	},
+	"env": {
+		Name:   "env",
+		Fn:     builtinEnv,
+		Params: []string{"name", "default"},
+	},
+	"exit": {
+		Name:   "exit",
+		Fn:     builtinExit,
+		Params: []string{"code"},
+	},
+	"sleep": {
+		Name: "sleep",
+		Fn:   builtinSleep,
+	},
+	"len": {
+		Name: "len",
+		Fn:   builtinLen,
+	},
+	"type": {
+		Name: "type",
+		Fn:   builtinType,
+	},
+	"str": {
+		Name: "str",
+		Fn:   builtinStr,
+	},
+	"int": {
+		Name: "int",
+		Fn:   builtinInt,
+	},
+	"float": {
+		Name: "float",
+		Fn:   builtinFloat,
+	},
+	"bool": {
+		Name: "bool",
+		Fn:   builtinBool,
+	},
+	"map": {
+		Name:   "map",
+		Fn:     builtinMap,
+		Params: []string{"list", "callback"},
+	},
+	"filter": {
+		Name:   "filter",
+		Fn:     builtinFilter,
+		Params: []string{"list", "predicate"},
+	},
+	"reduce": {
+		Name:   "reduce",
+		Fn:     builtinReduce,
+		Params: []string{"list", "callback", "initial"},
+	},
+	"sort": {
+		Name:   "sort",
+		Fn:     builtinSort,
+		Params: []string{"list", "key", "reverse"},
+	},
+	"sort_by": {
+		Name:   "sort_by",
+		Fn:     builtinSortBy,
+		Params: []string{"list", "key", "reverse"},
+	},
+	"group_by": {
+		Name:   "group_by",
+		Fn:     builtinGroupBy,
+		Params: []string{"list", "key"},
+	},
+	"unique": {
+		Name:   "unique",
+		Fn:     builtinUnique,
+		Params: []string{"list"},
+	},
+	"flatten": {
+		Name:   "flatten",
+		Fn:     builtinFlatten,
+		Params: []string{"list", "depth"},
+	},
+	"zip": {
+		Name: "zip",
+		Fn:   builtinZip,
+	},
+	"chunk": {
+		Name:   "chunk",
+		Fn:     builtinChunk,
+		Params: []string{"list", "size"},
+	},
+	"sum": {
+		Name:   "sum",
+		Fn:     builtinSum,
+		Params: []string{"list"},
+	},
+	"min": {
+		Name: "min",
+		Fn:   builtinMin,
+	},
+	"max": {
+		Name: "max",
+		Fn:   builtinMax,
+	},
+	"abs": {
+		Name:   "abs",
+		Fn:     builtinAbs,
+		Params: []string{"x"},
+	},
+	"floor": {
+		Name:   "floor",
+		Fn:     builtinFloor,
+		Params: []string{"x"},
+	},
+	"ceil": {
+		Name:   "ceil",
+		Fn:     builtinCeil,
+		Params: []string{"x"},
+	},
+	"round": {
+		Name:   "round",
+		Fn:     builtinRound,
+		Params: []string{"x", "digits"},
+	},
+	"sqrt": {
+		Name:   "sqrt",
+		Fn:     builtinSqrt,
+		Params: []string{"x"},
+	},
+	"pow": {
+		Name:   "pow",
+		Fn:     builtinPow,
+		Params: []string{"base", "exponent"},
+	},
+	"clamp": {
+		Name:   "clamp",
+		Fn:     builtinClamp,
+		Params: []string{"value", "low", "high"},
+	},
+	"mean": {
+		Name:   "mean",
+		Fn:     builtinMean,
+		Params: []string{"list"},
+	},
+	"median": {
+		Name:   "median",
+		Fn:     builtinMedian,
+		Params: []string{"list"},
+	},
+	"percentile": {
+		Name:   "percentile",
+		Fn:     builtinPercentile,
+		Params: []string{"list", "p"},
+	},
+	"any": {
+		Name:   "any",
+		Fn:     builtinAny,
+		Params: []string{"list", "predicate"},
+	},
+	"all": {
+		Name:   "all",
+		Fn:     builtinAll,
+		Params: []string{"list", "predicate"},
+	},
+	"starts_with": {
+		Name:   "starts_with",
+		Fn:     builtinStartsWith,
+		Params: []string{"string", "prefix"},
+	},
+	"ends_with": {
+		Name:   "ends_with",
+		Fn:     builtinEndsWith,
+		Params: []string{"string", "suffix"},
+	},
+	"contains": {
+		Name:   "contains",
+		Fn:     builtinContains,
+		Params: []string{"string", "substring"},
+	},
+	"index_of": {
+		Name:   "index_of",
+		Fn:     builtinIndexOf,
+		Params: []string{"string", "substring"},
+	},
+	"split": {
+		Name:   "split",
+		Fn:     builtinSplit,
+		Params: []string{"string", "separator", "limit"},
+	},
+	"join": {
+		Name:   "join",
+		Fn:     builtinJoin,
+		Params: []string{"list", "separator"},
+	},
+	"trim": {
+		Name:   "trim",
+		Fn:     builtinTrim,
+		Params: []string{"string", "chars"},
+	},
+	"replace": {
+		Name:   "replace",
+		Fn:     builtinReplace,
+		Params: []string{"string", "old", "new", "count"},
+	},
+	"upper": {
+		Name:   "upper",
+		Fn:     builtinUpper,
+		Params: []string{"string"},
+	},
+	"lower": {
+		Name:   "lower",
+		Fn:     builtinLower,
+		Params: []string{"string"},
+	},
+	"pad_left": {
+		Name:   "pad_left",
+		Fn:     builtinPadLeft,
+		Params: []string{"string", "width", "pad"},
+	},
+	"pad_right": {
+		Name:   "pad_right",
+		Fn:     builtinPadRight,
+		Params: []string{"string", "width", "pad"},
+	},
+	"repeat": {
+		Name:   "repeat",
+		Fn:     builtinRepeat,
+		Params: []string{"string", "count"},
+	},
+	"sprintf": {
+		Name: "sprintf",
+		Fn:   builtinSprintf,
+	},
+	"format_number": {
+		Name:   "format_number",
+		Fn:     builtinFormatNumber,
+		Params: []string{"number", "decimals", "separator"},
+	},
+	"keys": {
+		Name:   "keys",
+		Fn:     builtinKeys,
+		Params: []string{"object"},
+	},
+	"values": {
+		Name:   "values",
+		Fn:     builtinValues,
+		Params: []string{"object"},
+	},
+	"entries": {
+		Name:   "entries",
+		Fn:     builtinEntries,
+		Params: []string{"object"},
+	},
+	"has": {
+		Name:   "has",
+		Fn:     builtinHas,
+		Params: []string{"object", "key"},
+	},
+	"merge": {
+		Name: "merge",
+		Fn:   builtinMerge,
+	},
+	"deep_merge": {
+		Name: "deep_merge",
+		Fn:   builtinDeepMerge,
+	},
+	"pick": {
+		Name:   "pick",
+		Fn:     builtinPick,
+		Params: []string{"object", "keys"},
+	},
+	"omit": {
+		Name:   "omit",
+		Fn:     builtinOmit,
+		Params: []string{"object", "keys"},
+	},
+	"get_path": {
+		Name:   "get_path",
+		Fn:     builtinGetPath,
+		Params: []string{"object", "path", "default"},
+	},
+	"set_path": {
+		Name:   "set_path",
+		Fn:     builtinSetPath,
+		Params: []string{"object", "path", "value"},
+	},
+	"diff": {
+		Name:   "diff",
+		Fn:     builtinDiff,
+		Params: []string{"before", "after"},
+	},
+	"read_file": {
+		Name:   "read_file",
+		Fn:     builtinReadFile,
+		Params: []string{"path"},
+	},
+	"read_lines": {
+		Name:   "read_lines",
+		Fn:     builtinReadLines,
+		Params: []string{"path"},
+	},
+	"write_file": {
+		Name:   "write_file",
+		Fn:     builtinWriteFile,
+		Params: []string{"path", "content"},
+	},
+	"append_file": {
+		Name:   "append_file",
+		Fn:     builtinAppendFile,
+		Params: []string{"path", "content"},
+	},
+	"glob": {
+		Name:   "glob",
+		Fn:     builtinGlob,
+		Params: []string{"pattern"},
+	},
+	"exists": {
+		Name:   "exists",
+		Fn:     builtinExists,
+		Params: []string{"path"},
+	},
+	"mkdir": {
+		Name:   "mkdir",
+		Fn:     builtinMkdir,
+		Params: []string{"path"},
+	},
+	"remove": {
+		Name:   "remove",
+		Fn:     builtinRemove,
+		Params: []string{"path", "recursive"},
+	},
 }
 
-// RegisterBuiltins adds all built-in functions to the environment.
+// Namespaces contains the built-in namespaces available in AWSL.
+var Namespaces = map[string]*Namespace{
+	"json": {
+		Name: "json",
+		Members: map[string]Object{
+			"parse": &Builtin{
+				Name:   "json.parse",
+				Fn:     builtinJSONParse,
+				Params: []string{"text"},
+			},
+			"stringify": &Builtin{
+				Name:   "json.stringify",
+				Fn:     builtinJSONStringify,
+				Params: []string{"value", "indent"},
+			},
+		},
+	},
+	"yaml": {
+		Name: "yaml",
+		Members: map[string]Object{
+			"parse": &Builtin{
+				Name:   "yaml.parse",
+				Fn:     builtinYAMLParse,
+				Params: []string{"text"},
+			},
+			"stringify": &Builtin{
+				Name:   "yaml.stringify",
+				Fn:     builtinYAMLStringify,
+				Params: []string{"value"},
+			},
+		},
+	},
+	"toml": {
+		Name: "toml",
+		Members: map[string]Object{
+			"parse": &Builtin{
+				Name:   "toml.parse",
+				Fn:     builtinTOMLParse,
+				Params: []string{"text"},
+			},
+		},
+	},
+	"csv": {
+		Name: "csv",
+		Members: map[string]Object{
+			"parse": &Builtin{
+				Name:   "csv.parse",
+				Fn:     builtinCSVParse,
+				Params: []string{"text", "header", "delimiter", "infer"},
+			},
+		},
+	},
+	"log": {
+		Name: "log",
+		Call: &Builtin{
+			Name:   "log",
+			Fn:     builtinLog,
+			Params: []string{"x", "base"},
+		},
+		Members: map[string]Object{
+			"debug": &Builtin{
+				Name:   "log.debug",
+				Fn:     builtinLogDebug,
+				Params: []string{"message", "fields"},
+			},
+			"info": &Builtin{
+				Name:   "log.info",
+				Fn:     builtinLogInfo,
+				Params: []string{"message", "fields"},
+			},
+			"warn": &Builtin{
+				Name:   "log.warn",
+				Fn:     builtinLogWarn,
+				Params: []string{"message", "fields"},
+			},
+			"error": &Builtin{
+				Name:   "log.error",
+				Fn:     builtinLogError,
+				Params: []string{"message", "fields"},
+			},
+		},
+	},
+	"re": {
+		Name: "re",
+		Members: map[string]Object{
+			"compile": &Builtin{
+				Name:   "re.compile",
+				Fn:     builtinRegexCompile,
+				Params: []string{"pattern"},
+			},
+			"match": &Builtin{
+				Name:   "re.match",
+				Fn:     builtinRegexMatch,
+				Params: []string{"pattern", "string"},
+			},
+			"find_all": &Builtin{
+				Name:   "re.find_all",
+				Fn:     builtinRegexFindAll,
+				Params: []string{"pattern", "string", "limit"},
+			},
+			"replace": &Builtin{
+				Name:   "re.replace",
+				Fn:     builtinRegexReplace,
+				Params: []string{"pattern", "string", "replacement", "count"},
+			},
+			"split": &Builtin{
+				Name:   "re.split",
+				Fn:     builtinRegexSplit,
+				Params: []string{"pattern", "string", "limit"},
+			},
+		},
+	},
+}
+
+// typeNames maps object types to the names used by the language spec
+// and returned by type().
+var typeNames = map[ObjectType]string{
+	INTEGER_OBJ:   "int",
+	FLOAT_OBJ:     "float",
+	STRING_OBJ:    "string",
+	BOOLEAN_OBJ:   "bool",
+	NULL_OBJ:      "null",
+	LIST_OBJ:      "list",
+	HASH_OBJ:      "object",
+	DURATION_OBJ:  "duration",
+	TIME_OBJ:      "time",
+	FUNCTION_OBJ:  "function",
+	BUILTIN_OBJ:   "function",
+	NAMESPACE_OBJ: "namespace",
+	REGEX_OBJ:     "regex",
+}
+
+// RegisterBuiltins adds all built-in functions and namespaces to the
+// environment.
 func RegisterBuiltins(env *Environment) {
 	for name, builtin := range Builtins {
 		env.Set(name, builtin)
 	}
+	for name, namespace := range Namespaces {
+		env.Set(name, namespace)
+	}
 }
 
 // builtinPrint prints values to stdout separated by spaces.
@@ -41,3 +532,187 @@ func builtinPrint(env *Environment, args ...Object) Object {
 func builtinClock(env *Environment, args ...Object) Object {
 	return &Integer{Value: time.Now().Unix()}
 }
+
+// builtinNow gets the current time.
+// Returns Time in UTC.
+func builtinNow(env *Environment, args ...Object) Object {
+	if err := checkArgCount("now", args, 0); err != nil {
+		return err
+	}
+	return &Time{Value: time.Now().UTC()}
+}
+
+// builtinExit stops the script with an exit code, 0 by default. Nothing
+// after the call runs.
+// Returns an Error with the ExitRequest category.
+func builtinExit(env *Environment, args ...Object) Object {
+	if len(args) > 1 {
+		return newBuiltinError("wrong number of arguments to exit: expected 0 or 1, got %d", len(args))
+	}
+
+	code := int64(0)
+	if len(args) == 1 && args[0] != NULL {
+		var err *Error
+		code, err = integerArgument("exit", "", args[0])
+		if err != nil {
+			return err
+		}
+		if code < 0 || code > 255 {
+			return newBuiltinError("exit code must be between 0 and 255, got %d", code)
+		}
+	}
+	return &Error{
+		Message:  fmt.Sprintf("exit(%d)", code),
+		Category: ExitRequest,
+		ExitCode: int(code),
+	}
+}
+
+// builtinSleep pauses execution for the given duration.
+// Returns NULL.
+func builtinSleep(env *Environment, args ...Object) Object {
+	if err := checkArgCount("sleep", args, 1); err != nil {
+		return err
+	}
+
+	d, ok := args[0].(*Duration)
+	if !ok {
+		return newBuiltinError("argument to sleep must be DURATION, got %s", args[0].Type())
+	}
+	if d.Value < 0 {
+		return newBuiltinError("sleep duration must not be negative, got %s", d.Inspect())
+	}
+
+	time.Sleep(d.Value)
+	return NULL
+}
+
+// builtinLen returns the length of a string, list or object.
+// Strings are measured in characters (runes), not bytes.
+// Returns Integer.
+func builtinLen(env *Environment, args ...Object) Object {
+	if err := checkArgCount("len", args, 1); err != nil {
+		return err
+	}
+
+	switch arg := args[0].(type) {
+	case *String:
+		return &Integer{Value: int64(utf8.RuneCountInString(arg.Value))}
+	case *List:
+		return &Integer{Value: int64(len(arg.Elements))}
+	case *Hash:
+		return &Integer{Value: int64(arg.Len())}
+	default:
+		return newBuiltinError("argument to len not supported, got %s", arg.Type())
+	}
+}
+
+// builtinType returns the spec name of a value's type.
+// Returns String such as "int", "list" or "object".
+func builtinType(env *Environment, args ...Object) Object {
+	if err := checkArgCount("type", args, 1); err != nil {
+		return err
+	}
+	return &String{Value: typeName(args[0])}
+}
+
+// builtinStr converts a value to its string representation.
+// Returns String.
+func builtinStr(env *Environment, args ...Object) Object {
+	if err := checkArgCount("str", args, 1); err != nil {
+		return err
+	}
+	if s, ok := args[0].(*String); ok {
+		return s
+	}
+	return &String{Value: args[0].Inspect()}
+}
+
+// builtinInt converts a number, numeric string or boolean to an integer.
+// Floats are truncated toward zero.
+// Returns Integer.
+func builtinInt(env *Environment, args ...Object) Object {
+	if err := checkArgCount("int", args, 1); err != nil {
+		return err
+	}
+
+	switch arg := args[0].(type) {
+	case *Integer:
+		return arg
+	case *Float:
+		if math.IsNaN(arg.Value) || arg.Value >= math.MaxInt64 || arg.Value < math.MinInt64 {
+			return newBuiltinError("cannot convert %s to int: out of range", arg.Inspect())
+		}
+		return &Integer{Value: int64(arg.Value)}
+	case *String:
+		value, err := strconv.ParseInt(strings.TrimSpace(arg.Value), 10, 64)
+		if err != nil {
+			return newBuiltinError("cannot convert %q to int", arg.Value)
+		}
+		return &Integer{Value: value}
+	case *Boolean:
+		if arg.Value {
+			return &Integer{Value: 1}
+		}
+		return &Integer{Value: 0}
+	default:
+		return newBuiltinError("cannot convert %s to int", arg.Type())
+	}
+}
+
+// builtinFloat converts a number or numeric string to a float.
+// Returns Float.
+func builtinFloat(env *Environment, args ...Object) Object {
+	if err := checkArgCount("float", args, 1); err != nil {
+		return err
+	}
+
+	switch arg := args[0].(type) {
+	case *Float:
+		return arg
+	case *Integer:
+		return &Float{Value: float64(arg.Value)}
+	case *String:
+		value, err := strconv.ParseFloat(strings.TrimSpace(arg.Value), 64)
+		if err != nil {
+			return newBuiltinError("cannot convert %q to float", arg.Value)
+		}
+		return &Float{Value: value}
+	default:
+		return newBuiltinError("cannot convert %s to float", arg.Type())
+	}
+}
+
+// builtinBool converts a value to a boolean using the truthiness rules of
+// and, or and assert: false and null are false, everything else is true.
+// Returns Boolean.
+func builtinBool(env *Environment, args ...Object) Object {
+	if err := checkArgCount("bool", args, 1); err != nil {
+		return err
+	}
+	return nativeBoolToBooleanObject(isTruthy(args[0]))
+}
+
+// typeName returns the spec name of obj's type, falling back to the
+// internal type constant for types the spec does not name.
+func typeName(obj Object) string {
+	if name, ok := typeNames[obj.Type()]; ok {
+		return name
+	}
+	return string(obj.Type())
+}
+
+// checkArgCount returns an error if args does not have exactly
+// expected elements.
+func checkArgCount(name string, args []Object, expected int) *Error {
+	if len(args) != expected {
+		return newBuiltinError("wrong number of arguments to %s: expected %d, got %d", name, expected, len(args))
+	}
+	return nil
+}
+
+// newBuiltinError creates an Error without position information.
+// applyFunction fills in the position of the call site.
+func newBuiltinError(format string, args ...any) *Error {
+	return &Error{Message: fmt.Sprintf(format, args...)}
+}
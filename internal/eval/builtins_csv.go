@@ -0,0 +1,143 @@
+package eval
+
+import (
+	"encoding/csv"
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// builtinCSVParse decodes CSV text. With header (the default), the first
+// record names the columns and each following record becomes an object;
+// without it, each record becomes a list. With infer, fields that look
+// like integers, floats or booleans are converted; otherwise every field
+// is a string.
+// Returns a List.
+func builtinCSVParse(env *Environment, args ...Object) Object {
+	if len(args) < 1 || len(args) > 4 {
+		return newBuiltinError("wrong number of arguments to csv.parse: expected 1 to 4, got %d", len(args))
+	}
+	text, err := stringArgument("csv.parse", "", args[0])
+	if err != nil {
+		return err
+	}
+
+	header, err := optionalBoolArgument("csv.parse", "header", args, 1, true)
+	if err != nil {
+		return err
+	}
+	infer, err := optionalBoolArgument("csv.parse", "infer", args, 3, false)
+	if err != nil {
+		return err
+	}
+
+	delimiter := ','
+	if len(args) > 2 && args[2] != NULL {
+		s, err := stringArgument("csv.parse", "delimiter", args[2])
+		if err != nil {
+			return err
+		}
+		r, size := utf8.DecodeRuneInString(s)
+		if size == 0 || size != len(s) || r == '"' || r == '\r' || r == '\n' {
+			return newBuiltinError("csv.parse delimiter must be a single character other than a quote or newline, got %q", s)
+		}
+		delimiter = r
+	}
+
+	reader := csv.NewReader(strings.NewReader(text))
+	reader.Comma = delimiter
+
+	field := func(s string) Object {
+		if infer {
+			return inferCSVValue(s)
+		}
+		return &String{Value: s}
+	}
+
+	var columns []string
+	rows := []Object{}
+	for {
+		record, readErr := reader.Read()
+		if errors.Is(readErr, io.EOF) {
+			break
+		}
+		if readErr != nil {
+			return newBuiltinError("csv.parse: %s", readErr)
+		}
+
+		if header && columns == nil {
+			line, _ := reader.FieldPos(0)
+			seen := make(map[string]bool, len(record))
+			for _, name := range record {
+				if seen[name] {
+					return newBuiltinError("csv.parse: line %d: duplicate column %q", line, name)
+				}
+				seen[name] = true
+			}
+			columns = record
+			continue
+		}
+
+		if header {
+			row := NewHash()
+			for i, name := range columns {
+				row.Set(name, field(record[i]))
+			}
+			rows = append(rows, row)
+			continue
+		}
+
+		elements := make([]Object, len(record))
+		for i, value := range record {
+			elements[i] = field(value)
+		}
+		rows = append(rows, &List{Elements: elements})
+	}
+	return &List{Elements: rows}
+}
+
+// inferCSVValue converts a CSV field to a Boolean, Integer or Float if it
+// is written as one, and leaves it a String otherwise. Numbers with
+// leading zeros, such as ZIP codes and zero-padded IDs, stay strings, as
+// do empty fields.
+func inferCSVValue(s string) Object {
+	switch strings.ToLower(s) {
+	case "true":
+		return TRUE
+	case "false":
+		return FALSE
+	}
+
+	digits := strings.TrimPrefix(s, "-")
+	if digits == "" || digits[0] < '0' || digits[0] > '9' {
+		return &String{Value: s}
+	}
+	if len(digits) > 1 && digits[0] == '0' && digits[1] != '.' {
+		return &String{Value: s}
+	}
+
+	if value, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return &Integer{Value: value}
+	}
+	if strings.ContainsAny(s, ".eE") && !strings.ContainsAny(s, "_xXpP") {
+		if value, err := strconv.ParseFloat(s, 64); err == nil {
+			return &Float{Value: value}
+		}
+	}
+	return &String{Value: s}
+}
+
+// optionalBoolArgument returns args[index] as a bool, or fallback if the
+// argument was not given or is NULL.
+func optionalBoolArgument(name, param string, args []Object, index int, fallback bool) (bool, *Error) {
+	if len(args) <= index || args[index] == NULL {
+		return fallback, nil
+	}
+	b, ok := args[index].(*Boolean)
+	if !ok {
+		return false, argumentTypeError(name, param, BOOLEAN_OBJ, args[index])
+	}
+	return b.Value, nil
+}
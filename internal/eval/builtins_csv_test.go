@@ -0,0 +1,119 @@
+package eval
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestBuiltinCSVParse(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{"header", "pk,sk,count\nUSER#1,PROFILE,3\nUSER#2,ORDER,10\n", "[{pk: USER#1, sk: PROFILE, count: 3}, {pk: USER#2, sk: ORDER, count: 10}]"},
+		{"column order", "z,a\n1,2\n", "[{z: 1, a: 2}]"},
+		{"header only", "pk,sk\n", "[]"},
+		{"empty", "", "[]"},
+		{"quoted", "name,note\n\"Smith, J\",\"said \"\"hi\"\"\"\n", `[{name: Smith, J, note: said "hi"}]`},
+		{"multiline field", "id,text\n1,\"a\nb\"\n", "[{id: 1, text: a\nb}]"},
+		{"crlf", "a,b\r\n1,2\r\n", "[{a: 1, b: 2}]"},
+		{"no trailing newline", "a\n1", "[{a: 1}]"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := builtinCSVParse(nil, &String{Value: tt.input})
+			if result.Inspect() != tt.expected {
+				t.Errorf("wrong result. got=%q, want=%q", result.Inspect(), tt.expected)
+			}
+		})
+	}
+}
+
+func TestBuiltinCSVParseOptions(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []Object
+		expected string
+	}{
+		{"no header", []Object{&String{Value: "a,b\n1,2\n"}, FALSE}, "[[a, b], [1, 2]]"},
+		{"semicolon", []Object{&String{Value: "a;b\n1;2\n"}, NULL, &String{Value: ";"}}, "[{a: 1, b: 2}]"},
+		{"tab", []Object{&String{Value: "a\tb\n1,5\t2\n"}, TRUE, &String{Value: "\t"}}, "[{a: 1,5, b: 2}]"},
+		{"infer", []Object{&String{Value: "n,f,b,s,e\n42,2.5,true,x,\n"}, TRUE, NULL, TRUE}, "[{n: 42, f: 2.5, b: true, s: x, e: }]"},
+		{"infer without header", []Object{&String{Value: "1,FALSE,-3\n"}, FALSE, NULL, TRUE}, "[[1, false, -3]]"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := builtinCSVParse(nil, tt.args...)
+			if result.Inspect() != tt.expected {
+				t.Errorf("wrong result. got=%q, want=%q", result.Inspect(), tt.expected)
+			}
+		})
+	}
+}
+
+func TestInferCSVValue(t *testing.T) {
+	tests := []struct {
+		input        string
+		expectedType ObjectType
+	}{
+		{"42", INTEGER_OBJ},
+		{"-7", INTEGER_OBJ},
+		{"0", INTEGER_OBJ},
+		{"0.5", FLOAT_OBJ},
+		{"-1.25", FLOAT_OBJ},
+		{"1e3", FLOAT_OBJ},
+		{"true", BOOLEAN_OBJ},
+		{"False", BOOLEAN_OBJ},
+		{"02134", STRING_OBJ},
+		{"007", STRING_OBJ},
+		{"+5", STRING_OBJ},
+		{"1_000", STRING_OBJ},
+		{"0x1F", STRING_OBJ},
+		{"NaN", STRING_OBJ},
+		{"Inf", STRING_OBJ},
+		{"99999999999999999999", STRING_OBJ},
+		{"", STRING_OBJ},
+		{"-", STRING_OBJ},
+		{"yes", STRING_OBJ},
+		{"1.2.3", STRING_OBJ},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			result := inferCSVValue(tt.input)
+			if result.Type() != tt.expectedType {
+				t.Errorf("wrong type for %q. got=%s, want=%s", tt.input, result.Type(), tt.expectedType)
+			}
+		})
+	}
+}
+
+func TestBuiltinCSVErrors(t *testing.T) {
+	tests := []struct {
+		input           string
+		expectedMessage string
+	}{
+		{`csv.parse();`, "wrong number of arguments to csv.parse: expected 1 to 4, got 0"},
+		{`csv.parse(1);`, "argument to csv.parse must be STRING, got INTEGER"},
+		{`csv.parse("a", header: "yes");`, "csv.parse header must be BOOLEAN, got STRING"},
+		{`csv.parse("a", infer: 1);`, "csv.parse infer must be BOOLEAN, got INTEGER"},
+		{`csv.parse("a", delimiter: "::");`, `csv.parse delimiter must be a single character other than a quote or newline, got "::"`},
+		{`csv.parse("a", delimiter: "");`, `csv.parse delimiter must be a single character other than a quote or newline, got ""`},
+		{"csv.parse(\"a,b\n1,2\n3\n\");", "csv.parse: record on line 3: wrong number of fields"},
+		{"csv.parse(\"a,a\n1,2\n\");", `csv.parse: line 1: duplicate column "a"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			var stdout bytes.Buffer
+			evaluated := testEvalWithBuiltins(tt.input, &stdout)
+			testErrorObject(t, evaluated, tt.expectedMessage)
+		})
+	}
+
+	testErrorObject(t, builtinCSVParse(nil, &String{Value: "a,b\n1,x\"y\n"}),
+		`csv.parse: parse error on line 2, column 4: bare " in non-quoted-field`)
+}
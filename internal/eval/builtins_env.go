@@ -0,0 +1,86 @@
+package eval
+
+import (
+	"os"
+	"strings"
+)
+
+// builtinEnv reads an environment variable. An unset variable gives
+// fallback, or NULL if none is given; a variable set to the empty string
+// gives "".
+// Returns String, or fallback.
+func builtinEnv(env *Environment, args ...Object) Object {
+	if len(args) != 1 && len(args) != 2 {
+		return newBuiltinError("wrong number of arguments to env: expected 1 or 2, got %d", len(args))
+	}
+	name, err := stringArgument("env", "", args[0])
+	if err != nil {
+		return err
+	}
+	if name == "" {
+		return newBuiltinError("env name must not be empty")
+	}
+
+	if value, ok := os.LookupEnv(name); ok {
+		return &String{Value: value}
+	}
+	if len(args) == 2 {
+		return args[1]
+	}
+	return NULL
+}
+
+// RegisterScriptArgs binds args and flags to the command-line arguments
+// that follow the script name. See ParseScriptArgs.
+func RegisterScriptArgs(env *Environment, arguments []string) {
+	positional, flags := ParseScriptArgs(arguments)
+	env.Set("args", stringList(positional))
+	env.Set("flags", flags)
+}
+
+// ParseScriptArgs splits script arguments into positional arguments and
+// flags. A flag is written --name value, --name=value or -name value;
+// without a value, because it is last or followed by another flag, it
+// is true. Dashes inside names become underscores, so --dry-run is read
+// as flags.dry_run. A repeated flag keeps its last value. Everything
+// after -- is positional, as are negative numbers such as -5.
+func ParseScriptArgs(arguments []string) ([]string, *Hash) {
+	positional := []string{}
+	flags := NewHash()
+
+	for i := 0; i < len(arguments); i++ {
+		arg := arguments[i]
+		if arg == "--" {
+			positional = append(positional, arguments[i+1:]...)
+			break
+		}
+		if !isFlagArgument(arg) {
+			positional = append(positional, arg)
+			continue
+		}
+
+		name, value, hasValue := strings.Cut(strings.TrimLeft(arg, "-"), "=")
+		name = strings.ReplaceAll(name, "-", "_")
+		switch {
+		case hasValue:
+			flags.Set(name, &String{Value: value})
+		case i+1 < len(arguments) && !isFlagArgument(arguments[i+1]) && arguments[i+1] != "--":
+			i++
+			flags.Set(name, &String{Value: arguments[i]})
+		default:
+			flags.Set(name, TRUE)
+		}
+	}
+	return positional, flags
+}
+
+// isFlagArgument reports whether a script argument names a flag: it
+// starts with one or two dashes followed by something other than a
+// digit, dot or equals sign.
+func isFlagArgument(arg string) bool {
+	name := strings.TrimLeft(arg, "-")
+	if dashes := len(arg) - len(name); dashes == 0 || dashes > 2 || name == "" {
+		return false
+	}
+	return !strings.ContainsRune("0123456789.=", rune(name[0]))
+}
@@ -0,0 +1,102 @@
+package eval
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestBuiltinEnv(t *testing.T) {
+	t.Setenv("AWSL_TEST_STAGE", "prod")
+	t.Setenv("AWSL_TEST_EMPTY", "")
+
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`env("AWSL_TEST_STAGE");`, "prod"},
+		{`env("AWSL_TEST_STAGE", "dev");`, "prod"},
+		{`env("AWSL_TEST_EMPTY", "dev");`, ""},
+		{`env("AWSL_TEST_UNSET", "dev");`, "dev"},
+		{`env("AWSL_TEST_UNSET", 3);`, "3"},
+		{`env("AWSL_TEST_UNSET");`, "null"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			var stdout bytes.Buffer
+			evaluated := testEvalWithBuiltins(tt.input, &stdout)
+			if evaluated.Inspect() != tt.expected {
+				t.Errorf("wrong result. got=%q, want=%q", evaluated.Inspect(), tt.expected)
+			}
+		})
+	}
+}
+
+func TestBuiltinEnvErrors(t *testing.T) {
+	tests := []struct {
+		input           string
+		expectedMessage string
+	}{
+		{`env();`, "wrong number of arguments to env: expected 1 or 2, got 0"},
+		{`env(1);`, "argument to env must be STRING, got INTEGER"},
+		{`env("");`, "env name must not be empty"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			var stdout bytes.Buffer
+			evaluated := testEvalWithBuiltins(tt.input, &stdout)
+			testErrorObject(t, evaluated, tt.expectedMessage)
+		})
+	}
+}
+
+func TestParseScriptArgs(t *testing.T) {
+	tests := []struct {
+		input              string
+		expectedPositional string
+		expectedFlags      string
+	}{
+		{"", "[]", "{}"},
+		{"--stage prod extra", "[extra]", "{stage: prod}"},
+		{"--stage=prod --count=3", "[]", "{stage: prod, count: 3}"},
+		{"--dry-run --stage prod", "[]", "{dry_run: true, stage: prod}"},
+		{"a --verbose", "[a]", "{verbose: true}"},
+		{"-n 5 -x", "[]", "{n: 5, x: true}"},
+		{"--offset -5 -1.5", "[-1.5]", "{offset: -5}"},
+		{"--stage dev --stage prod", "[]", "{stage: prod}"},
+		{"--empty= a", "[a]", "{empty: }"},
+		{"a -- --stage prod", "[a, --stage, prod]", "{}"},
+		{"--flag -- b", "[b]", "{flag: true}"},
+		{"- --- ---x", "[-, ---, ---x]", "{}"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			positional, flags := ParseScriptArgs(strings.Fields(tt.input))
+			if got := stringList(positional).Inspect(); got != tt.expectedPositional {
+				t.Errorf("wrong positional args. got=%q, want=%q", got, tt.expectedPositional)
+			}
+			if got := flags.Inspect(); got != tt.expectedFlags {
+				t.Errorf("wrong flags. got=%q, want=%q", got, tt.expectedFlags)
+			}
+		})
+	}
+}
+
+func TestRegisterScriptArgs(t *testing.T) {
+	var stdout bytes.Buffer
+	env := NewEnvironment(&stdout)
+	RegisterBuiltins(env)
+	RegisterScriptArgs(env, []string{"--stage", "prod", "users.csv"})
+
+	args, _ := env.Get("args")
+	if args.Inspect() != "[users.csv]" {
+		t.Errorf("wrong args. got=%q", args.Inspect())
+	}
+	flags, _ := env.Get("flags")
+	if flags.Inspect() != "{stage: prod}" {
+		t.Errorf("wrong flags. got=%q", flags.Inspect())
+	}
+}
@@ -0,0 +1,238 @@
+package eval
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// builtinReadFile reads the whole contents of a file.
+// Returns String.
+func builtinReadFile(env *Environment, args ...Object) Object {
+	if err := checkArgCount("read_file", args, 1); err != nil {
+		return err
+	}
+	path, err := pathArgument(env, "read_file", args[0])
+	if err != nil {
+		return err
+	}
+
+	data, readErr := os.ReadFile(path)
+	if readErr != nil {
+		return fsError("read_file", args[0], readErr)
+	}
+	return &String{Value: string(data)}
+}
+
+// builtinReadLines reads a file and splits it into lines. Line endings
+// (\n or \r\n) are removed, and a final newline does not start an extra
+// empty line.
+// Returns a List of Strings.
+func builtinReadLines(env *Environment, args ...Object) Object {
+	if err := checkArgCount("read_lines", args, 1); err != nil {
+		return err
+	}
+	path, err := pathArgument(env, "read_lines", args[0])
+	if err != nil {
+		return err
+	}
+
+	data, readErr := os.ReadFile(path)
+	if readErr != nil {
+		return fsError("read_lines", args[0], readErr)
+	}
+
+	text := strings.TrimSuffix(string(data), "\n")
+	if text == "" {
+		return &List{Elements: []Object{}}
+	}
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimSuffix(line, "\r")
+	}
+	return stringList(lines)
+}
+
+// builtinWriteFile replaces the contents of a file, creating it if it
+// does not exist.
+// Returns NULL.
+func builtinWriteFile(env *Environment, args ...Object) Object {
+	return writeFile(env, "write_file", args, os.O_TRUNC)
+}
+
+// builtinAppendFile adds to the end of a file, creating it if it does
+// not exist.
+// Returns NULL.
+func builtinAppendFile(env *Environment, args ...Object) Object {
+	return writeFile(env, "append_file", args, os.O_APPEND)
+}
+
+// writeFile implements write_file and append_file; mode is the flag that
+// distinguishes them.
+func writeFile(env *Environment, name string, args []Object, mode int) Object {
+	if err := checkArgCount(name, args, 2); err != nil {
+		return err
+	}
+	path, err := writablePathArgument(env, name, args[0])
+	if err != nil {
+		return err
+	}
+	content, err := stringArgument(name, "content", args[1])
+	if err != nil {
+		return err
+	}
+
+	file, openErr := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|mode, 0o644)
+	if openErr != nil {
+		return fsError(name, args[0], openErr)
+	}
+	if _, writeErr := file.WriteString(content); writeErr != nil {
+		file.Close()
+		return fsError(name, args[0], writeErr)
+	}
+	if closeErr := file.Close(); closeErr != nil {
+		return fsError(name, args[0], closeErr)
+	}
+	return NULL
+}
+
+// builtinGlob lists the paths matching a shell pattern, in sorted order.
+// Relative patterns give paths relative to the script directory, so they
+// can be passed straight to the other file functions.
+// Returns a List of Strings.
+func builtinGlob(env *Environment, args ...Object) Object {
+	if err := checkArgCount("glob", args, 1); err != nil {
+		return err
+	}
+	pattern, err := stringArgument("glob", "", args[0])
+	if err != nil {
+		return err
+	}
+
+	matches, globErr := filepath.Glob(resolvePath(env, pattern))
+	if globErr != nil {
+		return newBuiltinError("glob: invalid pattern %q", pattern)
+	}
+
+	if !filepath.IsAbs(pattern) && env.Dir() != "" {
+		for i, match := range matches {
+			if rel, relErr := filepath.Rel(env.Dir(), match); relErr == nil {
+				matches[i] = rel
+			}
+		}
+	}
+	sort.Strings(matches)
+	return stringList(matches)
+}
+
+// builtinExists reports whether a file or directory exists.
+// Returns Boolean.
+func builtinExists(env *Environment, args ...Object) Object {
+	if err := checkArgCount("exists", args, 1); err != nil {
+		return err
+	}
+	path, err := pathArgument(env, "exists", args[0])
+	if err != nil {
+		return err
+	}
+
+	_, statErr := os.Stat(path)
+	if statErr != nil && !errors.Is(statErr, fs.ErrNotExist) {
+		return fsError("exists", args[0], statErr)
+	}
+	return nativeBoolToBooleanObject(statErr == nil)
+}
+
+// builtinMkdir creates a directory along with any missing parents. It is
+// not an error for the directory to exist already.
+// Returns NULL.
+func builtinMkdir(env *Environment, args ...Object) Object {
+	if err := checkArgCount("mkdir", args, 1); err != nil {
+		return err
+	}
+	path, err := writablePathArgument(env, "mkdir", args[0])
+	if err != nil {
+		return err
+	}
+
+	if mkdirErr := os.MkdirAll(path, 0o755); mkdirErr != nil {
+		return fsError("mkdir", args[0], mkdirErr)
+	}
+	return NULL
+}
+
+// builtinRemove deletes a file or empty directory. With recursive set, a
+// directory is deleted along with everything in it.
+// Returns Boolean: false if there was nothing to remove.
+func builtinRemove(env *Environment, args ...Object) Object {
+	if len(args) != 1 && len(args) != 2 {
+		return newBuiltinError("wrong number of arguments to remove: expected 1 or 2, got %d", len(args))
+	}
+	path, err := writablePathArgument(env, "remove", args[0])
+	if err != nil {
+		return err
+	}
+
+	recursive, err := optionalBoolArgument("remove", "recursive", args, 1, false)
+	if err != nil {
+		return err
+	}
+
+	if _, statErr := os.Lstat(path); errors.Is(statErr, fs.ErrNotExist) {
+		return FALSE
+	}
+
+	var removeErr error
+	if recursive {
+		removeErr = os.RemoveAll(path)
+	} else {
+		removeErr = os.Remove(path)
+	}
+	if removeErr != nil {
+		return fsError("remove", args[0], removeErr)
+	}
+	return TRUE
+}
+
+// pathArgument unwraps a path argument and resolves it against the
+// script directory.
+func pathArgument(env *Environment, name string, arg Object) (string, *Error) {
+	path, err := stringArgument(name, "path", arg)
+	if err != nil {
+		return "", err
+	}
+	if path == "" {
+		return "", newBuiltinError("%s path must not be empty", name)
+	}
+	return resolvePath(env, path), nil
+}
+
+// writablePathArgument is pathArgument for builtins that modify the
+// filesystem; it fails if writes are denied.
+func writablePathArgument(env *Environment, name string, arg Object) (string, *Error) {
+	if env.DenyWrites() {
+		return "", newBuiltinError("%s: filesystem writes are disabled (--read-only)", name)
+	}
+	return pathArgument(env, name, arg)
+}
+
+// resolvePath makes a relative path relative to the script directory.
+func resolvePath(env *Environment, path string) string {
+	if filepath.IsAbs(path) || env.Dir() == "" {
+		return path
+	}
+	return filepath.Join(env.Dir(), path)
+}
+
+// fsError reports a failed filesystem operation on the path as the
+// script gave it, without the resolved path the os package includes.
+func fsError(name string, path Object, err error) *Error {
+	var pathErr *fs.PathError
+	if errors.As(err, &pathErr) {
+		err = pathErr.Err
+	}
+	return newBuiltinError("%s: %s: %s", name, path.Inspect(), err)
+}
@@ -0,0 +1,133 @@
+package eval
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/boattime/awsl/internal/lexer"
+	"github.com/boattime/awsl/internal/parser"
+)
+
+// testEvalInDir evaluates input with relative paths resolving to dir.
+func testEvalInDir(input, dir string, denyWrites bool) Object {
+	program := parser.New(lexer.New(input)).ParseProgram()
+	env := NewEnvironment(&bytes.Buffer{})
+	env.SetDir(dir)
+	env.SetDenyWrites(denyWrites)
+	RegisterBuiltins(env)
+	return Eval(program, env)
+}
+
+// writeTestFile creates a file under dir with the given contents.
+func writeTestFile(t *testing.T, dir, name, contents string) {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(contents), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestBuiltinFileFunctions(t *testing.T) {
+	dir := t.TempDir()
+	writeTestFile(t, dir, "ids.txt", "USER#1\nUSER#2\r\nUSER#3\n")
+	writeTestFile(t, dir, "empty.txt", "")
+	writeTestFile(t, dir, "data/a.csv", "a")
+	writeTestFile(t, dir, "data/b.csv", "b")
+	writeTestFile(t, dir, "data/c.json", "{}")
+
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`read_file("data/a.csv");`, "a"},
+		{`read_file("` + filepath.Join(dir, "data/b.csv") + `");`, "b"},
+		{`read_lines("ids.txt");`, "[USER#1, USER#2, USER#3]"},
+		{`read_lines("empty.txt");`, "[]"},
+		{`len(read_lines(path: "ids.txt"));`, "3"},
+		{`glob("data/*.csv");`, "[" + filepath.Join("data", "a.csv") + ", " + filepath.Join("data", "b.csv") + "]"},
+		{`glob("data/*.yaml");`, "[]"},
+		{`exists("ids.txt");`, "true"},
+		{`exists("data");`, "true"},
+		{`exists("missing.txt");`, "false"},
+		{"write_file(\"out.txt\", \"one\n\"); append_file(\"out.txt\", \"two\n\"); read_lines(\"out.txt\");", "[one, two]"},
+		{`write_file("new.txt", "x"); write_file("new.txt", "y"); read_file("new.txt");`, "y"},
+		{`append_file("log.txt", "first"); read_file("log.txt");`, "first"},
+		{`mkdir("a/b/c"); mkdir("a/b/c"); exists("a/b/c");`, "true"},
+		{`write_file("gone.txt", ""); [remove("gone.txt"), exists("gone.txt"), remove("gone.txt")];`, "[true, false, false]"},
+		{`mkdir("tree/sub"); write_file("tree/sub/f", "x"); remove("tree", recursive: true);`, "true"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			evaluated := testEvalInDir(tt.input, dir, false)
+			if evaluated.Inspect() != tt.expected {
+				t.Errorf("wrong result. got=%q, want=%q", evaluated.Inspect(), tt.expected)
+			}
+		})
+	}
+}
+
+func TestBuiltinFileErrors(t *testing.T) {
+	dir := t.TempDir()
+	writeTestFile(t, dir, "full/file.txt", "x")
+
+	tests := []struct {
+		input           string
+		expectedMessage string
+	}{
+		{`read_file("missing.txt");`, "read_file: missing.txt: no such file or directory"},
+		{`read_lines("missing.txt");`, "read_lines: missing.txt: no such file or directory"},
+		{`read_file(1);`, "read_file path must be STRING, got INTEGER"},
+		{`read_file("");`, "read_file path must not be empty"},
+		{`write_file("out.txt", 1);`, "write_file content must be STRING, got INTEGER"},
+		{`write_file("nodir/out.txt", "x");`, "write_file: nodir/out.txt: no such file or directory"},
+		{`glob("[");`, `glob: invalid pattern "["`},
+		{`remove("full");`, "remove: full: directory not empty"},
+		{`remove("full", "yes");`, "remove recursive must be BOOLEAN, got STRING"},
+		{`exists();`, "wrong number of arguments to exists: expected 1, got 0"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			testErrorObject(t, testEvalInDir(tt.input, dir, false), tt.expectedMessage)
+		})
+	}
+}
+
+func TestBuiltinFileDenyWrites(t *testing.T) {
+	dir := t.TempDir()
+	writeTestFile(t, dir, "keep.txt", "x")
+
+	tests := []struct {
+		input           string
+		expectedMessage string
+	}{
+		{`write_file("out.txt", "x");`, "write_file: filesystem writes are disabled (--read-only)"},
+		{`append_file("keep.txt", "x");`, "append_file: filesystem writes are disabled (--read-only)"},
+		{`mkdir("dir");`, "mkdir: filesystem writes are disabled (--read-only)"},
+		{`remove("keep.txt");`, "remove: filesystem writes are disabled (--read-only)"},
+		{`fn f() { return write_file("out.txt", "x"); } f();`, "write_file: filesystem writes are disabled (--read-only)"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			testErrorObject(t, testEvalInDir(tt.input, dir, true), tt.expectedMessage)
+		})
+	}
+
+	if result := testEvalInDir(`read_file("keep.txt");`, dir, true); result.Inspect() != "x" {
+		t.Errorf("reads should be allowed. got=%q", result.Inspect())
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 {
+		t.Errorf("expected only keep.txt in %s, got %d entries", dir, len(entries))
+	}
+}
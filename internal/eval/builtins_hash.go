@@ -0,0 +1,452 @@
+package eval
+
+import (
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// builtinKeys returns the keys of an object in sorted order.
+// Returns a List of Strings.
+func builtinKeys(env *Environment, args ...Object) Object {
+	hash, err := hashArgument("keys", args)
+	if err != nil {
+		return err
+	}
+	return stringList(sortedKeys(hash))
+}
+
+// builtinValues returns the values of an object, ordered by key.
+// Returns List.
+func builtinValues(env *Environment, args ...Object) Object {
+	hash, err := hashArgument("values", args)
+	if err != nil {
+		return err
+	}
+
+	keys := sortedKeys(hash)
+	values := make([]Object, len(keys))
+	for i, key := range keys {
+		values[i], _ = hash.Get(key)
+	}
+	return &List{Elements: values}
+}
+
+// builtinEntries returns the key-value pairs of an object, ordered by
+// key.
+// Returns a List of [key, value] Lists.
+func builtinEntries(env *Environment, args ...Object) Object {
+	hash, err := hashArgument("entries", args)
+	if err != nil {
+		return err
+	}
+
+	keys := sortedKeys(hash)
+	entries := make([]Object, len(keys))
+	for i, key := range keys {
+		value, _ := hash.Get(key)
+		entries[i] = &List{Elements: []Object{&String{Value: key}, value}}
+	}
+	return &List{Elements: entries}
+}
+
+// builtinHas reports whether an object has a key.
+// Returns Boolean.
+func builtinHas(env *Environment, args ...Object) Object {
+	if err := checkArgCount("has", args, 2); err != nil {
+		return err
+	}
+	hash, ok := args[0].(*Hash)
+	if !ok {
+		return newBuiltinError("argument to has must be HASH, got %s", args[0].Type())
+	}
+	key, err := stringArgument("has", "key", args[1])
+	if err != nil {
+		return err
+	}
+
+	_, exists := hash.Get(key)
+	return nativeBoolToBooleanObject(exists)
+}
+
+// builtinMerge combines objects into a new object. When several objects
+// have the same key, the value from the last one wins.
+// Returns Hash.
+func builtinMerge(env *Environment, args ...Object) Object {
+	return mergeHashes("merge", args, false)
+}
+
+// builtinDeepMerge combines objects like merge, but merges nested
+// objects key by key instead of replacing them.
+// Returns Hash.
+func builtinDeepMerge(env *Environment, args ...Object) Object {
+	return mergeHashes("deep_merge", args, true)
+}
+
+// mergeHashes implements merge and deep_merge.
+func mergeHashes(name string, args []Object, deep bool) Object {
+	if len(args) < 1 {
+		return newBuiltinError("wrong number of arguments to %s: expected at least 1, got 0", name)
+	}
+
+	result := NewHash()
+	for i, arg := range args {
+		hash, ok := arg.(*Hash)
+		if !ok {
+			return newBuiltinError("argument %d to %s must be HASH, got %s", i+1, name, arg.Type())
+		}
+		mergeInto(result, hash, deep)
+	}
+	return result
+}
+
+// mergeInto copies the pairs of src into dst. With deep set, objects
+// present in both are merged recursively into a fresh object so that
+// neither input is modified. Keys new to dst are added in src's order.
+func mergeInto(dst, src *Hash, deep bool) {
+	for _, pair := range src.Pairs() {
+		key, value := pair.Key, pair.Value
+		if deep {
+			current, _ := dst.Get(key)
+			existing, dstIsHash := current.(*Hash)
+			incoming, srcIsHash := value.(*Hash)
+			if dstIsHash && srcIsHash {
+				merged := NewHash()
+				mergeInto(merged, existing, false)
+				mergeInto(merged, incoming, true)
+				value = merged
+			}
+		}
+		dst.Set(key, value)
+	}
+}
+
+// builtinPick returns a new object with only the given keys. Keys that
+// are missing from the input are skipped.
+// Returns Hash.
+func builtinPick(env *Environment, args ...Object) Object {
+	hash, keys, err := hashAndKeys("pick", args)
+	if err != nil {
+		return err
+	}
+
+	result := NewHash()
+	for _, key := range keys {
+		if value, ok := hash.Get(key); ok {
+			result.Set(key, value)
+		}
+	}
+	return result
+}
+
+// builtinOmit returns a new object without the given keys, keeping the
+// order of the remaining keys.
+// Returns Hash.
+func builtinOmit(env *Environment, args ...Object) Object {
+	hash, keys, err := hashAndKeys("omit", args)
+	if err != nil {
+		return err
+	}
+
+	result := NewHash()
+	for _, pair := range hash.Pairs() {
+		if !slices.Contains(keys, pair.Key) {
+			result.Set(pair.Key, pair.Value)
+		}
+	}
+	return result
+}
+
+// builtinGetPath looks up a nested value by a path such as "a.b[0].c".
+// Missing keys and out-of-range indexes yield the default (null unless
+// given) rather than an error.
+// Returns the value found or the default.
+func builtinGetPath(env *Environment, args ...Object) Object {
+	if len(args) != 2 && len(args) != 3 {
+		return newBuiltinError("wrong number of arguments to get_path: expected 2 or 3, got %d", len(args))
+	}
+	path, err := stringArgument("get_path", "path", args[1])
+	if err != nil {
+		return err
+	}
+	segments, err := parsePath(path)
+	if err != nil {
+		return err
+	}
+
+	fallback := Object(NULL)
+	if len(args) == 3 {
+		fallback = args[2]
+	}
+
+	current := args[0]
+	for _, segment := range segments {
+		next, ok := segment.lookup(current)
+		if !ok {
+			return fallback
+		}
+		current = next
+	}
+	return current
+}
+
+// builtinSetPath returns a copy of an object or list with the value at
+// path replaced. Objects along the path are copied, not modified, and
+// missing object keys are created as empty objects.
+// Returns the updated copy.
+func builtinSetPath(env *Environment, args ...Object) Object {
+	if err := checkArgCount("set_path", args, 3); err != nil {
+		return err
+	}
+	path, err := stringArgument("set_path", "path", args[1])
+	if err != nil {
+		return err
+	}
+	segments, err := parsePath(path)
+	if err != nil {
+		return err
+	}
+
+	return setPath(args[0], segments, args[2])
+}
+
+// setPath returns a copy of container with segments[0] set to the result
+// of applying the remaining segments to its current value.
+func setPath(container Object, segments []pathSegment, value Object) Object {
+	if len(segments) == 0 {
+		return value
+	}
+	segment, rest := segments[0], segments[1:]
+
+	if !segment.isIndex {
+		hash, ok := container.(*Hash)
+		if !ok {
+			return newBuiltinError("set_path: cannot set key %q on %s", segment.key, container.Type())
+		}
+
+		child, ok := hash.Get(segment.key)
+		if !ok {
+			child = NewHash()
+		}
+		updated := setPath(child, rest, value)
+		if isError(updated) {
+			return updated
+		}
+
+		result := NewHash()
+		mergeInto(result, hash, false)
+		result.Set(segment.key, updated)
+		return result
+	}
+
+	list, ok := container.(*List)
+	if !ok {
+		return newBuiltinError("set_path: cannot index %s with [%d]", container.Type(), segment.index)
+	}
+	index, ok := segment.resolve(list)
+	if !ok {
+		return newBuiltinError("set_path: index out of bounds: %d (length: %d)", segment.index, len(list.Elements))
+	}
+
+	updated := setPath(list.Elements[index], rest, value)
+	if isError(updated) {
+		return updated
+	}
+
+	elements := make([]Object, len(list.Elements))
+	copy(elements, list.Elements)
+	elements[index] = updated
+	return &List{Elements: elements}
+}
+
+// builtinDiff compares two objects and reports what changed from the
+// first to the second. Nested objects are compared key by key and their
+// keys are reported as paths such as "config.memory"; lists and other
+// values are compared as a whole.
+// Returns a Hash {added, removed, changed}, where added and removed map
+// paths to values and changed maps paths to {from, to} objects.
+func builtinDiff(env *Environment, args ...Object) Object {
+	if err := checkArgCount("diff", args, 2); err != nil {
+		return err
+	}
+	before, ok := args[0].(*Hash)
+	if !ok {
+		return newBuiltinError("argument 1 to diff must be HASH, got %s", args[0].Type())
+	}
+	after, ok := args[1].(*Hash)
+	if !ok {
+		return newBuiltinError("argument 2 to diff must be HASH, got %s", args[1].Type())
+	}
+
+	added, removed, changed := NewHash(), NewHash(), NewHash()
+	diffHashes("", before, after, added, removed, changed)
+	return NewHash(
+		HashPair{Key: "added", Value: added},
+		HashPair{Key: "removed", Value: removed},
+		HashPair{Key: "changed", Value: changed},
+	)
+}
+
+// diffHashes records the differences between before and after under the
+// given path prefix.
+func diffHashes(prefix string, before, after, added, removed, changed *Hash) {
+	for _, pair := range before.Pairs() {
+		path := prefix + pair.Key
+		other, exists := after.Get(pair.Key)
+		switch {
+		case !exists:
+			removed.Set(path, pair.Value)
+		case objectsEqual(pair.Value, other):
+		default:
+			beforeHash, beforeIsHash := pair.Value.(*Hash)
+			afterHash, afterIsHash := other.(*Hash)
+			if beforeIsHash && afterIsHash {
+				diffHashes(path+".", beforeHash, afterHash, added, removed, changed)
+				continue
+			}
+			changed.Set(path, NewHash(
+				HashPair{Key: "from", Value: pair.Value},
+				HashPair{Key: "to", Value: other},
+			))
+		}
+	}
+
+	for _, pair := range after.Pairs() {
+		if _, exists := before.Get(pair.Key); !exists {
+			added.Set(prefix+pair.Key, pair.Value)
+		}
+	}
+}
+
+// pathSegment is one step of a get_path/set_path path: an object key or
+// a list index.
+type pathSegment struct {
+	key     string
+	index   int64
+	isIndex bool
+}
+
+// lookup applies the segment to obj, reporting false if the key or index
+// is not present.
+func (s pathSegment) lookup(obj Object) (Object, bool) {
+	if !s.isIndex {
+		hash, ok := obj.(*Hash)
+		if !ok {
+			return nil, false
+		}
+		return hash.Get(s.key)
+	}
+
+	list, ok := obj.(*List)
+	if !ok {
+		return nil, false
+	}
+	index, ok := s.resolve(list)
+	if !ok {
+		return nil, false
+	}
+	return list.Elements[index], true
+}
+
+// resolve converts the segment's index, which may count from the end if
+// negative, to a position in list.
+func (s pathSegment) resolve(list *List) (int64, bool) {
+	index := s.index
+	length := int64(len(list.Elements))
+	if index < 0 {
+		index += length
+	}
+	return index, index >= 0 && index < length
+}
+
+// parsePath splits a path such as "a.b[0].c" into segments.
+func parsePath(path string) ([]pathSegment, *Error) {
+	if path == "" {
+		return nil, newBuiltinError("invalid path %q: path is empty", path)
+	}
+
+	var segments []pathSegment
+	rest := path
+	expectKey := true
+	for rest != "" {
+		switch {
+		case rest[0] == '[':
+			end := strings.IndexByte(rest, ']')
+			if end < 0 {
+				return nil, newBuiltinError("invalid path %q: missing ]", path)
+			}
+			index, err := strconv.ParseInt(rest[1:end], 10, 64)
+			if err != nil {
+				return nil, newBuiltinError("invalid path %q: index %q is not an integer", path, rest[1:end])
+			}
+			segments = append(segments, pathSegment{index: index, isIndex: true})
+			rest = rest[end+1:]
+			expectKey = false
+		case rest[0] == '.' && !expectKey:
+			rest = rest[1:]
+			expectKey = true
+			if rest == "" {
+				return nil, newBuiltinError("invalid path %q: ends with .", path)
+			}
+		case expectKey:
+			end := strings.IndexAny(rest, ".[]")
+			if end < 0 {
+				end = len(rest)
+			}
+			if end == 0 {
+				return nil, newBuiltinError("invalid path %q: empty key", path)
+			}
+			segments = append(segments, pathSegment{key: rest[:end]})
+			rest = rest[end:]
+			expectKey = false
+		default:
+			return nil, newBuiltinError("invalid path %q: unexpected %q", path, rest[:1])
+		}
+	}
+	return segments, nil
+}
+
+// hashArgument validates the single object argument shared by keys,
+// values and entries.
+func hashArgument(name string, args []Object) (*Hash, *Error) {
+	if err := checkArgCount(name, args, 1); err != nil {
+		return nil, err
+	}
+	hash, ok := args[0].(*Hash)
+	if !ok {
+		return nil, newBuiltinError("argument to %s must be HASH, got %s", name, args[0].Type())
+	}
+	return hash, nil
+}
+
+// hashAndKeys validates the (object, keys) arguments of pick and omit.
+func hashAndKeys(name string, args []Object) (*Hash, []string, *Error) {
+	if err := checkArgCount(name, args, 2); err != nil {
+		return nil, nil, err
+	}
+	hash, ok := args[0].(*Hash)
+	if !ok {
+		return nil, nil, newBuiltinError("argument to %s must be HASH, got %s", name, args[0].Type())
+	}
+	list, ok := args[1].(*List)
+	if !ok {
+		return nil, nil, newBuiltinError("%s keys must be LIST, got %s", name, args[1].Type())
+	}
+
+	keys := make([]string, len(list.Elements))
+	for i, elem := range list.Elements {
+		key, ok := elem.(*String)
+		if !ok {
+			return nil, nil, newBuiltinError("%s keys must be STRING, got %s at index %d", name, elem.Type(), i)
+		}
+		keys[i] = key.Value
+	}
+	return hash, keys, nil
+}
+
+// sortedKeys returns the keys of hash in ascending order.
+func sortedKeys(hash *Hash) []string {
+	keys := slices.Clone(hash.Keys())
+	slices.Sort(keys)
+	return keys
+}
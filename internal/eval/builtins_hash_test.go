@@ -0,0 +1,89 @@
+package eval
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestBuiltinHashFunctions(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`keys({b: 1, a: 2, c: 3});`, "[a, b, c]"},
+		{`keys({});`, "[]"},
+		{`values({b: 1, a: 2});`, "[2, 1]"},
+		{`entries({b: 1, a: 2});`, "[[a, 2], [b, 1]]"},
+		{`has({pk: "x"}, "pk");`, "true"},
+		{`has({pk: "x"}, "sk");`, "false"},
+		{`has({pk: null}, "pk");`, "true"},
+		{`entries(merge({a: 1, b: 1}, {b: 2}, {c: 3}));`, "[[a, 1], [b, 2], [c, 3]]"},
+		{`merge({z: 1, a: 1}, {m: 2, z: 2});`, "{z: 2, a: 1, m: 2}"},
+		{`omit({z: 1, a: 2, m: 3}, ["a"]);`, "{z: 1, m: 3}"},
+		{`pick({z: 1, a: 2, m: 3}, ["m", "z"]);`, "{m: 3, z: 1}"},
+		{`merge({a: {x: 1}}, {a: {y: 2}});`, "{a: {y: 2}}"},
+		{`a = deep_merge({a: {x: 1, y: 1}}, {a: {y: 2}}); entries(a.a);`, "[[x, 1], [y, 2]]"},
+		{`base = {a: {x: 1}}; deep_merge(base, {a: {y: 2}}); keys(base.a);`, "[x]"},
+		{`deep_merge({a: {x: 1}}, {a: 5});`, "{a: 5}"},
+		{`item = {pk: "U#1", sk: "P", name: "n"}; entries(pick(item, ["pk", "sk", "nope"]));`, "[[pk, U#1], [sk, P]]"},
+		{`item = {pk: "U#1", sk: "P", name: "n"}; keys(omit(item, ["pk", "sk"]));`, "[name]"},
+		{`get_path({a: {b: [{c: 42}]}}, "a.b[0].c");`, "42"},
+		{`get_path({a: [1, 2, 3]}, "a[-1]");`, "3"},
+		{`get_path([[1, 2]], "[0][1]");`, "2"},
+		{`get_path({a: 1}, "a.b.c");`, "null"},
+		{`get_path({a: [1]}, "a[5]", "none");`, "none"},
+		{`get_path({a: 1}, "missing", default: 0);`, "0"},
+		{`set_path({}, "a.b", 1);`, "{a: {b: 1}}"},
+		{`set_path({a: [1, 2]}, "a[1]", 9);`, "{a: [1, 9]}"},
+		{`item = {a: {b: 1}}; set_path(item, "a.b", 2); item.a.b;`, "1"},
+		{`list = [{n: 1}]; set_path(list, "[0].n", 2);`, "[{n: 2}]"},
+		{`diff({a: 1, b: 2}, {a: 1, b: 2});`, "{added: {}, removed: {}, changed: {}}"},
+		{`diff({a: 1, b: 2, c: 3}, {a: 1, b: 5, d: 4});`, "{added: {d: 4}, removed: {c: 3}, changed: {b: {from: 2, to: 5}}}"},
+		{`diff({cfg: {mem: 128, env: {x: 1}}}, {cfg: {mem: 256, env: {x: 1, y: 2}}});`, "{added: {cfg.env.y: 2}, removed: {}, changed: {cfg.mem: {from: 128, to: 256}}}"},
+		{`diff({tags: [1, 2]}, {tags: [1, 2, 3]});`, "{added: {}, removed: {}, changed: {tags: {from: [1, 2], to: [1, 2, 3]}}}"},
+		{`diff({a: {x: 1}}, {a: 5});`, "{added: {}, removed: {}, changed: {a: {from: {x: 1}, to: 5}}}"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			var stdout bytes.Buffer
+			evaluated := testEvalWithBuiltins(tt.input, &stdout)
+			if evaluated.Inspect() != tt.expected {
+				t.Errorf("wrong result. got=%s, want=%s", evaluated.Inspect(), tt.expected)
+			}
+		})
+	}
+}
+
+func TestBuiltinHashErrors(t *testing.T) {
+	tests := []struct {
+		input           string
+		expectedMessage string
+	}{
+		{`keys([1]);`, "argument to keys must be HASH, got LIST"},
+		{`values();`, "wrong number of arguments to values: expected 1, got 0"},
+		{`has({}, 1);`, "has key must be STRING, got INTEGER"},
+		{`merge();`, "wrong number of arguments to merge: expected at least 1, got 0"},
+		{`merge({}, 1);`, "argument 2 to merge must be HASH, got INTEGER"},
+		{`pick({}, "pk");`, "pick keys must be LIST, got STRING"},
+		{`omit({}, [1]);`, "omit keys must be STRING, got INTEGER at index 0"},
+		{`get_path({}, "");`, `invalid path "": path is empty`},
+		{`get_path({}, "a..b");`, `invalid path "a..b": empty key`},
+		{`get_path({}, "a.");`, `invalid path "a.": ends with .`},
+		{`get_path({}, "a[x]");`, `invalid path "a[x]": index "x" is not an integer`},
+		{`get_path({}, "a[0");`, `invalid path "a[0": missing ]`},
+		{`get_path({}, "a[0]b");`, `invalid path "a[0]b": unexpected "b"`},
+		{`set_path({a: 1}, "a.b", 2);`, `set_path: cannot set key "b" on INTEGER`},
+		{`set_path({a: {}}, "a[0]", 2);`, "set_path: cannot index HASH with [0]"},
+		{`set_path({a: [1]}, "a[3]", 2);`, "set_path: index out of bounds: 3 (length: 1)"},
+		{`diff({}, [1]);`, "argument 2 to diff must be HASH, got LIST"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			var stdout bytes.Buffer
+			evaluated := testEvalWithBuiltins(tt.input, &stdout)
+			testErrorObject(t, evaluated, tt.expectedMessage)
+		})
+	}
+}
@@ -0,0 +1,276 @@
+package eval
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// builtinJSONParse decodes a JSON document. Objects keep their key
+// order, numbers without a fraction or exponent become integers and all
+// other numbers become floats.
+// Returns the decoded value.
+func builtinJSONParse(env *Environment, args ...Object) Object {
+	if err := checkArgCount("json.parse", args, 1); err != nil {
+		return err
+	}
+	text, err := stringArgument("json.parse", "", args[0])
+	if err != nil {
+		return err
+	}
+
+	value, err := decodeJSON(text)
+	if err != nil {
+		return newBuiltinError("json.parse: %s", err.Message)
+	}
+	return value
+}
+
+// builtinJSONStringify encodes a value as JSON. With a positive indent,
+// the output is spread over multiple lines with that many spaces per
+// level; otherwise it is compact.
+// Returns String.
+func builtinJSONStringify(env *Environment, args ...Object) Object {
+	if len(args) != 1 && len(args) != 2 {
+		return newBuiltinError("wrong number of arguments to json.stringify: expected 1 or 2, got %d", len(args))
+	}
+
+	indent := int64(0)
+	if len(args) == 2 && args[1] != NULL {
+		var err *Error
+		indent, err = integerArgument("json.stringify", "indent", args[1])
+		if err != nil {
+			return err
+		}
+		if indent < 0 || indent > 16 {
+			return newBuiltinError("json.stringify indent must be between 0 and 16, got %d", indent)
+		}
+	}
+
+	text, err := encodeJSON(args[0], strings.Repeat(" ", int(indent)))
+	if err != nil {
+		return newBuiltinError("json.stringify: %s", err.Message)
+	}
+	return &String{Value: text}
+}
+
+// decodeJSON converts a JSON document to objects. Errors carry the byte
+// offset in text where decoding failed.
+func decodeJSON(text string) (Object, *Error) {
+	// Validate the whole document first: the token decoder below reports
+	// some syntax errors at the wrong place.
+	var raw json.RawMessage
+	if err := json.Unmarshal([]byte(text), &raw); err != nil {
+		return nil, jsonSyntaxError(err, len(text))
+	}
+
+	dec := json.NewDecoder(strings.NewReader(text))
+	dec.UseNumber()
+	return decodeJSONValue(dec)
+}
+
+// decodeJSONValue reads one complete JSON value from dec.
+func decodeJSONValue(dec *json.Decoder) (Object, *Error) {
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, jsonSyntaxError(err, int(dec.InputOffset()))
+	}
+
+	switch tok := tok.(type) {
+	case json.Delim:
+		if tok == '{' {
+			hash := NewHash()
+			for dec.More() {
+				keyTok, err := dec.Token()
+				if err != nil {
+					return nil, jsonSyntaxError(err, int(dec.InputOffset()))
+				}
+				value, errObj := decodeJSONValue(dec)
+				if errObj != nil {
+					return nil, errObj
+				}
+				hash.Set(keyTok.(string), value)
+			}
+			if _, err := dec.Token(); err != nil {
+				return nil, jsonSyntaxError(err, int(dec.InputOffset()))
+			}
+			return hash, nil
+		}
+
+		elements := []Object{}
+		for dec.More() {
+			value, errObj := decodeJSONValue(dec)
+			if errObj != nil {
+				return nil, errObj
+			}
+			elements = append(elements, value)
+		}
+		if _, err := dec.Token(); err != nil {
+			return nil, jsonSyntaxError(err, int(dec.InputOffset()))
+		}
+		return &List{Elements: elements}, nil
+	case json.Number:
+		return decodeJSONNumber(tok, int(dec.InputOffset())-len(tok))
+	case string:
+		return &String{Value: tok}, nil
+	case bool:
+		return nativeBoolToBooleanObject(tok), nil
+	default:
+		return NULL, nil
+	}
+}
+
+// decodeJSONNumber converts a JSON number starting at offset to an
+// Integer, or to a Float if it has a fraction or exponent.
+func decodeJSONNumber(number json.Number, offset int) (Object, *Error) {
+	literal := number.String()
+	if strings.ContainsAny(literal, ".eE") {
+		value, err := strconv.ParseFloat(literal, 64)
+		if err != nil {
+			return nil, newBuiltinError("number %s is out of range at offset %d", literal, offset)
+		}
+		return &Float{Value: value}, nil
+	}
+
+	value, err := strconv.ParseInt(literal, 10, 64)
+	if err != nil {
+		return nil, newBuiltinError("integer %s overflows int64 at offset %d", literal, offset)
+	}
+	return &Integer{Value: value}, nil
+}
+
+// jsonSyntaxError converts a decoding error to an Error with the offset
+// at which it occurred.
+func jsonSyntaxError(err error, offset int) *Error {
+	var syntaxErr *json.SyntaxError
+	if errors.As(err, &syntaxErr) {
+		offset = int(syntaxErr.Offset)
+	}
+	return newBuiltinError("%s at offset %d", err.Error(), offset)
+}
+
+// encodeJSON converts an object to JSON text. Lists and hashes that
+// contain themselves are rejected rather than encoded forever.
+func encodeJSON(obj Object, indent string) (string, *Error) {
+	var out strings.Builder
+	enc := &jsonEncoder{out: &out, indent: indent, visiting: make(map[Object]bool)}
+	if err := enc.encode(obj, 0); err != nil {
+		return "", err
+	}
+	return out.String(), nil
+}
+
+// jsonEncoder holds the state of a single encodeJSON call.
+type jsonEncoder struct {
+	out      *strings.Builder
+	indent   string
+	visiting map[Object]bool
+}
+
+// encode writes obj at the given nesting depth.
+func (e *jsonEncoder) encode(obj Object, depth int) *Error {
+	switch obj := obj.(type) {
+	case *Null:
+		e.out.WriteString("null")
+	case *Boolean:
+		e.out.WriteString(strconv.FormatBool(obj.Value))
+	case *Integer:
+		e.out.WriteString(strconv.FormatInt(obj.Value, 10))
+	case *Float:
+		if math.IsNaN(obj.Value) || math.IsInf(obj.Value, 0) {
+			return newBuiltinError("cannot encode %s as JSON", obj.Inspect())
+		}
+		e.out.WriteString(formatJSONFloat(obj.Value))
+	case *String:
+		e.writeString(obj.Value)
+	case *Duration:
+		e.writeString(obj.Inspect())
+	case *Time:
+		e.writeString(obj.Value.Format(time.RFC3339Nano))
+	case *List:
+		if e.visiting[obj] {
+			return newBuiltinError("cannot encode cyclic LIST as JSON")
+		}
+		e.visiting[obj] = true
+		defer delete(e.visiting, obj)
+
+		e.out.WriteString("[")
+		for i, elem := range obj.Elements {
+			e.separator(i, depth+1)
+			if err := e.encode(elem, depth+1); err != nil {
+				return err
+			}
+		}
+		e.closing(len(obj.Elements), depth)
+		e.out.WriteString("]")
+	case *Hash:
+		if e.visiting[obj] {
+			return newBuiltinError("cannot encode cyclic HASH as JSON")
+		}
+		e.visiting[obj] = true
+		defer delete(e.visiting, obj)
+
+		e.out.WriteString("{")
+		for i, pair := range obj.Pairs() {
+			e.separator(i, depth+1)
+			e.writeString(pair.Key)
+			e.out.WriteString(":")
+			if e.indent != "" {
+				e.out.WriteString(" ")
+			}
+			if err := e.encode(pair.Value, depth+1); err != nil {
+				return err
+			}
+		}
+		e.closing(obj.Len(), depth)
+		e.out.WriteString("}")
+	default:
+		return newBuiltinError("cannot encode %s as JSON", obj.Type())
+	}
+	return nil
+}
+
+// separator writes the comma and indentation before the i-th element of
+// a list or hash.
+func (e *jsonEncoder) separator(i, depth int) {
+	if i > 0 {
+		e.out.WriteString(",")
+	}
+	if e.indent != "" {
+		e.out.WriteString("\n")
+		e.out.WriteString(strings.Repeat(e.indent, depth))
+	}
+}
+
+// closing writes the indentation before the closing bracket of a
+// non-empty list or hash.
+func (e *jsonEncoder) closing(length, depth int) {
+	if e.indent != "" && length > 0 {
+		e.out.WriteString("\n")
+		e.out.WriteString(strings.Repeat(e.indent, depth))
+	}
+}
+
+// writeString writes s as a quoted JSON string without escaping HTML
+// characters.
+func (e *jsonEncoder) writeString(s string) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	_ = enc.Encode(s) // encoding a string cannot fail
+	e.out.Write(bytes.TrimSuffix(buf.Bytes(), []byte("\n")))
+}
+
+// formatJSONFloat formats a float so that it reads back as a float:
+// whole numbers keep a ".0" suffix.
+func formatJSONFloat(value float64) string {
+	text := strconv.FormatFloat(value, 'g', -1, 64)
+	if !strings.ContainsAny(text, ".eE") {
+		text += ".0"
+	}
+	return text
+}
@@ -0,0 +1,157 @@
+package eval
+
+import (
+	"bytes"
+	"math"
+	"testing"
+)
+
+func TestBuiltinJSONParse(t *testing.T) {
+	tests := []struct {
+		input        string
+		expected     string
+		expectedType string
+	}{
+		{`42`, "42", "int"},
+		{`-7`, "-7", "int"},
+		{`9223372036854775807`, "9223372036854775807", "int"},
+		{`42.0`, "42", "float"},
+		{`1e3`, "1000", "float"},
+		{`[1, 2.5, true, false, null]`, "[1, 2.5, true, false, null]", "list"},
+		{`[]`, "[]", "list"},
+		{`{}`, "{}", "object"},
+		{`{"b": 1, "a": {"z": [1], "y": null}}`, "{b: 1, a: {z: [1], y: null}}", "object"},
+		{`{"a": 1, "a": 2}`, "{a: 2}", "object"},
+		{`"line\nbreak \u00e9 <tag>"`, "line\nbreak é <tag>", "string"},
+		{"  [1]  \n", "[1]", "list"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			result := builtinJSONParse(nil, &String{Value: tt.input})
+			if isError(result) {
+				t.Fatalf("unexpected error: %s", result.Inspect())
+			}
+			if result.Inspect() != tt.expected {
+				t.Errorf("wrong result. got=%q, want=%q", result.Inspect(), tt.expected)
+			}
+			if typeName(result) != tt.expectedType {
+				t.Errorf("wrong type. got=%s, want=%s", typeName(result), tt.expectedType)
+			}
+		})
+	}
+}
+
+func TestBuiltinJSONParseErrors(t *testing.T) {
+	tests := []struct {
+		input           string
+		expectedMessage string
+	}{
+		{``, "json.parse: unexpected end of JSON input at offset 0"},
+		{`[1, 2`, "json.parse: unexpected end of JSON input at offset 5"},
+		{`[1,]`, "json.parse: invalid character ']' looking for beginning of value at offset 4"},
+		{`{"a" 1}`, "json.parse: invalid character '1' after object key at offset 6"},
+		{`[1] [2]`, "json.parse: invalid character '[' after top-level value at offset 5"},
+		{`[1, 99999999999999999999]`, "json.parse: integer 99999999999999999999 overflows int64 at offset 4"},
+		{`1e999`, "json.parse: number 1e999 is out of range at offset 0"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			testErrorObject(t, builtinJSONParse(nil, &String{Value: tt.input}), tt.expectedMessage)
+		})
+	}
+}
+
+func TestBuiltinJSONStringify(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`json.stringify(null);`, "null"},
+		{`json.stringify(true);`, "true"},
+		{`json.stringify(42);`, "42"},
+		{`json.stringify(2.5);`, "2.5"},
+		{`json.stringify(2.0);`, "2.0"},
+		{`json.stringify("a<b>&c");`, `"a<b>&c"`},
+		{`json.stringify([1, "x", [null]]);`, `[1,"x",[null]]`},
+		{`json.stringify({z: 1, a: 2});`, `{"z":1,"a":2}`},
+		{`json.stringify(90s);`, `"1m30s"`},
+		{`json.stringify({a: [1, 2], b: {}}, 2);`, "{\n  \"a\": [\n    1,\n    2\n  ],\n  \"b\": {}\n}"},
+		{`json.stringify([], indent: 4);`, "[]"},
+		{`json.stringify([1], indent: 0);`, "[1]"},
+		{`json.stringify(value: [1], indent: null);`, "[1]"},
+		{"json.stringify(\"tab\\there\");", `"tab\\there"`},
+		{`x = {b: [1.5, "s"], a: {c: null}}; json.parse(json.stringify(x)) == x;`, "true"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			var stdout bytes.Buffer
+			evaluated := testEvalWithBuiltins(tt.input, &stdout)
+			if evaluated.Inspect() != tt.expected {
+				t.Errorf("wrong result. got=%q, want=%q", evaluated.Inspect(), tt.expected)
+			}
+		})
+	}
+}
+
+func TestBuiltinJSONErrors(t *testing.T) {
+	tests := []struct {
+		input           string
+		expectedMessage string
+	}{
+		{`json.parse();`, "wrong number of arguments to json.parse: expected 1, got 0"},
+		{`json.parse(1);`, "argument to json.parse must be STRING, got INTEGER"},
+		{`json.parse("[1, 2");`, "json.parse: unexpected end of JSON input at offset 5"},
+		{`json.parse(txt: "1");`, `unknown argument "txt" to json.parse`},
+		{`json.stringify();`, "wrong number of arguments to json.stringify: expected 1 or 2, got 0"},
+		{`json.stringify(1, "2");`, "json.stringify indent must be INTEGER, got STRING"},
+		{`json.stringify(1, -1);`, "json.stringify indent must be between 0 and 16, got -1"},
+		{`json.stringify(print);`, "json.stringify: cannot encode BUILTIN as JSON"},
+		{`fn f(x) { return x; } json.stringify({f: f});`, "json.stringify: cannot encode FUNCTION as JSON"},
+		{`json.stringify(json);`, "json.stringify: cannot encode NAMESPACE as JSON"},
+		{`json.foo;`, "undefined member: json.foo"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			var stdout bytes.Buffer
+			evaluated := testEvalWithBuiltins(tt.input, &stdout)
+			testErrorObject(t, evaluated, tt.expectedMessage)
+		})
+	}
+}
+
+func TestJSONStringifyRejectsUnencodableValues(t *testing.T) {
+	cyclicList := &List{}
+	cyclicList.Elements = []Object{&Integer{Value: 1}, cyclicList}
+
+	cyclicHash := NewHash()
+	cyclicHash.Set("self", cyclicHash)
+
+	shared := &List{Elements: []Object{&Integer{Value: 1}}}
+
+	tests := []struct {
+		name            string
+		value           Object
+		expectedMessage string
+	}{
+		{"cyclic list", cyclicList, "json.stringify: cannot encode cyclic LIST as JSON"},
+		{"cyclic hash", cyclicHash, "json.stringify: cannot encode cyclic HASH as JSON"},
+		{"nan", &Float{Value: math.NaN()}, "json.stringify: cannot encode NaN as JSON"},
+		{"infinity", &Float{Value: math.Inf(1)}, "json.stringify: cannot encode +Inf as JSON"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			testErrorObject(t, builtinJSONStringify(nil, tt.value), tt.expectedMessage)
+		})
+	}
+
+	// A value referenced twice is not a cycle.
+	result := builtinJSONStringify(nil, &List{Elements: []Object{shared, shared}})
+	if result.Inspect() != "[[1],[1]]" {
+		t.Errorf("wrong result for shared list. got=%q", result.Inspect())
+	}
+}
@@ -0,0 +1,615 @@
+package eval
+
+import (
+	"cmp"
+	"slices"
+
+	"github.com/boattime/awsl/internal/ast"
+	"github.com/boattime/awsl/internal/token"
+)
+
+// builtinMap applies fn to each element of a list.
+// Returns a new List of the results.
+func builtinMap(env *Environment, args ...Object) Object {
+	list, fn, err := listAndCallback("map", args)
+	if err != nil {
+		return err
+	}
+
+	result := make([]Object, len(list.Elements))
+	for i, elem := range list.Elements {
+		value := callFunction(env, fn, elem)
+		if isError(value) {
+			return value
+		}
+		result[i] = value
+	}
+	return &List{Elements: result}
+}
+
+// builtinFilter keeps the elements of a list for which fn returns a
+// truthy value.
+// Returns a new List.
+func builtinFilter(env *Environment, args ...Object) Object {
+	list, fn, err := listAndCallback("filter", args)
+	if err != nil {
+		return err
+	}
+
+	result := []Object{}
+	for _, elem := range list.Elements {
+		keep := callFunction(env, fn, elem)
+		if isError(keep) {
+			return keep
+		}
+		if isTruthy(keep) {
+			result = append(result, elem)
+		}
+	}
+	return &List{Elements: result}
+}
+
+// builtinReduce folds a list into a single value by calling
+// fn(accumulator, element) for each element. Without an initial value
+// the first element is used as the starting accumulator.
+// Returns the final accumulator.
+func builtinReduce(env *Environment, args ...Object) Object {
+	if len(args) != 2 && len(args) != 3 {
+		return newBuiltinError("wrong number of arguments to reduce: expected 2 or 3, got %d", len(args))
+	}
+	list, fn, err := listAndCallback("reduce", args[:2])
+	if err != nil {
+		return err
+	}
+
+	elements := list.Elements
+	var acc Object
+	if len(args) == 3 && args[2] != NULL {
+		acc = args[2]
+	} else {
+		if len(elements) == 0 {
+			return newBuiltinError("reduce of empty list with no initial value")
+		}
+		acc, elements = elements[0], elements[1:]
+	}
+
+	for _, elem := range elements {
+		acc = callFunction(env, fn, acc, elem)
+		if isError(acc) {
+			return acc
+		}
+	}
+	return acc
+}
+
+// builtinSort sorts a list in ascending order. The optional key function
+// maps each element to the value that is compared, and reverse sorts in
+// descending order. The sort is stable and the input is not modified.
+// Returns a new List.
+func builtinSort(env *Environment, args ...Object) Object {
+	if len(args) < 1 || len(args) > 3 {
+		return newBuiltinError("wrong number of arguments to sort: expected 1 to 3, got %d", len(args))
+	}
+	list, ok := args[0].(*List)
+	if !ok {
+		return newBuiltinError("argument to sort must be LIST, got %s", args[0].Type())
+	}
+
+	key := Object(NULL)
+	if len(args) > 1 {
+		key = args[1]
+	}
+	reverse := Object(FALSE)
+	if len(args) > 2 && args[2] != NULL {
+		reverse = args[2]
+	}
+	return sortList(env, "sort", list, key, reverse)
+}
+
+// builtinSortBy sorts a list by a key, which is either a function applied
+// to each element or the name of a field of each object element.
+// Returns a new List.
+func builtinSortBy(env *Environment, args ...Object) Object {
+	if len(args) != 2 && len(args) != 3 {
+		return newBuiltinError("wrong number of arguments to sort_by: expected 2 or 3, got %d", len(args))
+	}
+	list, ok := args[0].(*List)
+	if !ok {
+		return newBuiltinError("argument to sort_by must be LIST, got %s", args[0].Type())
+	}
+	if args[1] == NULL {
+		return newBuiltinError("sort_by requires a key")
+	}
+
+	reverse := Object(FALSE)
+	if len(args) > 2 && args[2] != NULL {
+		reverse = args[2]
+	}
+	return sortList(env, "sort_by", list, args[1], reverse)
+}
+
+// builtinGroupBy groups the elements of a list by the key returned from
+// fn, or by a named field of each object element. Keys may be any
+// hashable value; string keys name their group directly and other keys
+// are named by their printed form.
+// Returns a Hash mapping each key to a List of elements, in the order
+// the keys were first seen.
+func builtinGroupBy(env *Environment, args ...Object) Object {
+	if err := checkArgCount("group_by", args, 2); err != nil {
+		return err
+	}
+	list, ok := args[0].(*List)
+	if !ok {
+		return newBuiltinError("argument to group_by must be LIST, got %s", args[0].Type())
+	}
+
+	keys, err := sortKeys(env, "group_by", list, args[1])
+	if err != nil {
+		return err
+	}
+
+	result := NewHash()
+	groups := make(map[string]*List)
+	names := make(map[string]string)
+	for i, elem := range list.Elements {
+		key, ok := hashKey(keys[i])
+		if !ok {
+			return newBuiltinError("group_by key must be hashable, got %s", keys[i].Type())
+		}
+
+		group, exists := groups[key]
+		if !exists {
+			name := groupName(keys[i])
+			if other, taken := names[name]; taken && other != key {
+				return newBuiltinError("group_by keys of different types both print as %q", name)
+			}
+			names[name] = key
+			group = &List{}
+			groups[key] = group
+			result.Set(name, group)
+		}
+		group.Elements = append(group.Elements, elem)
+	}
+	return result
+}
+
+// builtinUnique removes duplicate elements from a list, keeping the first
+// occurrence of each value. Lists and objects are compared by value;
+// elements that cannot be hashed, such as functions, are compared one by
+// one. Returns a new List.
+func builtinUnique(env *Environment, args ...Object) Object {
+	if err := checkArgCount("unique", args, 1); err != nil {
+		return err
+	}
+	list, ok := args[0].(*List)
+	if !ok {
+		return newBuiltinError("argument to unique must be LIST, got %s", args[0].Type())
+	}
+
+	result := []Object{}
+	seen := make(map[string]bool)
+	var unhashable []Object
+	for _, elem := range list.Elements {
+		key, ok := hashKey(elem)
+		if !ok {
+			if !slices.ContainsFunc(unhashable, func(other Object) bool { return objectsEqual(elem, other) }) {
+				unhashable = append(unhashable, elem)
+				result = append(result, elem)
+			}
+			continue
+		}
+		if !seen[key] {
+			seen[key] = true
+			result = append(result, elem)
+		}
+	}
+	return &List{Elements: result}
+}
+
+// builtinFlatten concatenates nested lists into one list. The optional
+// depth (default 1) limits how many levels of nesting are removed.
+// Returns a new List.
+func builtinFlatten(env *Environment, args ...Object) Object {
+	if len(args) != 1 && len(args) != 2 {
+		return newBuiltinError("wrong number of arguments to flatten: expected 1 or 2, got %d", len(args))
+	}
+	list, ok := args[0].(*List)
+	if !ok {
+		return newBuiltinError("argument to flatten must be LIST, got %s", args[0].Type())
+	}
+
+	depth := int64(1)
+	if len(args) == 2 && args[1] != NULL {
+		d, ok := args[1].(*Integer)
+		if !ok {
+			return newBuiltinError("flatten depth must be INTEGER, got %s", args[1].Type())
+		}
+		if d.Value < 0 {
+			return newBuiltinError("flatten depth must not be negative, got %d", d.Value)
+		}
+		depth = d.Value
+	}
+
+	return &List{Elements: flattenElements(list.Elements, depth)}
+}
+
+// flattenElements appends the elements of nested lists in place of the
+// lists themselves, descending at most depth levels.
+func flattenElements(elements []Object, depth int64) []Object {
+	result := []Object{}
+	for _, elem := range elements {
+		if inner, ok := elem.(*List); ok && depth > 0 {
+			result = append(result, flattenElements(inner.Elements, depth-1)...)
+		} else {
+			result = append(result, elem)
+		}
+	}
+	return result
+}
+
+// builtinZip pairs up the elements of two or more lists by position.
+// The result is as long as the shortest input.
+// Returns a List of Lists.
+func builtinZip(env *Environment, args ...Object) Object {
+	if len(args) < 2 {
+		return newBuiltinError("wrong number of arguments to zip: expected at least 2, got %d", len(args))
+	}
+
+	lists := make([]*List, len(args))
+	length := -1
+	for i, arg := range args {
+		list, ok := arg.(*List)
+		if !ok {
+			return newBuiltinError("argument %d to zip must be LIST, got %s", i+1, arg.Type())
+		}
+		lists[i] = list
+		if length < 0 || len(list.Elements) < length {
+			length = len(list.Elements)
+		}
+	}
+
+	result := make([]Object, length)
+	for i := range result {
+		tuple := make([]Object, len(lists))
+		for j, list := range lists {
+			tuple[j] = list.Elements[i]
+		}
+		result[i] = &List{Elements: tuple}
+	}
+	return &List{Elements: result}
+}
+
+// builtinChunk splits a list into consecutive lists of at most size
+// elements.
+// Returns a List of Lists.
+func builtinChunk(env *Environment, args ...Object) Object {
+	if err := checkArgCount("chunk", args, 2); err != nil {
+		return err
+	}
+	list, ok := args[0].(*List)
+	if !ok {
+		return newBuiltinError("argument to chunk must be LIST, got %s", args[0].Type())
+	}
+	size, ok := args[1].(*Integer)
+	if !ok {
+		return newBuiltinError("chunk size must be INTEGER, got %s", args[1].Type())
+	}
+	if size.Value <= 0 {
+		return newBuiltinError("chunk size must be positive, got %d", size.Value)
+	}
+
+	result := []Object{}
+	for start := 0; start < len(list.Elements); start += int(size.Value) {
+		end := min(start+int(size.Value), len(list.Elements))
+		result = append(result, &List{Elements: slices.Clone(list.Elements[start:end])})
+	}
+	return &List{Elements: result}
+}
+
+// builtinSum adds the elements of a list. Integers sum to an integer,
+// any float makes the result a float, and durations sum to a duration.
+// Returns 0 for an empty list.
+func builtinSum(env *Environment, args ...Object) Object {
+	if err := checkArgCount("sum", args, 1); err != nil {
+		return err
+	}
+	list, ok := args[0].(*List)
+	if !ok {
+		return newBuiltinError("argument to sum must be LIST, got %s", args[0].Type())
+	}
+	if len(list.Elements) == 0 {
+		return &Integer{Value: 0}
+	}
+
+	var acc Object
+	for _, elem := range list.Elements {
+		if acc == nil {
+			acc = elem
+		} else {
+			acc = addObjects(acc, elem)
+		}
+		switch acc.(type) {
+		case *Integer, *Float, *Duration:
+		case *Error:
+			return acc
+		default:
+			return newBuiltinError("sum requires numbers or durations, got %s", elem.Type())
+		}
+	}
+	return acc
+}
+
+// addObjects adds two numbers or durations, promoting an integer to a
+// float when the other operand is a float.
+func addObjects(left, right Object) Object {
+	switch {
+	case left.Type() == INTEGER_OBJ && right.Type() == FLOAT_OBJ:
+		left = &Float{Value: float64(left.(*Integer).Value)}
+	case left.Type() == FLOAT_OBJ && right.Type() == INTEGER_OBJ:
+		right = &Float{Value: float64(right.(*Integer).Value)}
+	case left.Type() != right.Type():
+		return newBuiltinError("sum requires numbers or durations, got %s and %s", left.Type(), right.Type())
+	}
+
+	switch left.(type) {
+	case *Integer:
+		return evalIntegerInfixExpression(token.PLUS, left, right, ast.Position{})
+	case *Float:
+		return evalFloatInfixExpression(token.PLUS, left, right, ast.Position{})
+	case *Duration:
+		return evalDurationInfixExpression(token.PLUS, left, right, ast.Position{})
+	default:
+		return newBuiltinError("sum requires numbers or durations, got %s", left.Type())
+	}
+}
+
+// builtinMin returns the smallest element of a list, or the smallest of
+// several arguments.
+func builtinMin(env *Environment, args ...Object) Object {
+	return extremum("min", args, -1)
+}
+
+// builtinMax returns the largest element of a list, or the largest of
+// several arguments.
+func builtinMax(env *Environment, args ...Object) Object {
+	return extremum("max", args, 1)
+}
+
+// extremum implements min and max. want is -1 to find the smallest value
+// and 1 to find the largest.
+func extremum(name string, args []Object, want int) Object {
+	values := args
+	if len(args) == 1 {
+		list, ok := args[0].(*List)
+		if !ok {
+			return newBuiltinError("argument to %s must be LIST, got %s", name, args[0].Type())
+		}
+		values = list.Elements
+	}
+	if len(values) == 0 {
+		return newBuiltinError("%s of empty list", name)
+	}
+
+	best := values[0]
+	for _, value := range values[1:] {
+		c, ok := compareObjects(value, best)
+		if !ok {
+			return newBuiltinError("cannot compare %s and %s", value.Type(), best.Type())
+		}
+		if c == want {
+			best = value
+		}
+	}
+	return best
+}
+
+// builtinAny reports whether any element of a list is truthy, or whether
+// fn returns a truthy value for any element.
+// Returns Boolean.
+func builtinAny(env *Environment, args ...Object) Object {
+	return quantify(env, "any", args, true)
+}
+
+// builtinAll reports whether every element of a list is truthy, or
+// whether fn returns a truthy value for every element.
+// Returns Boolean.
+func builtinAll(env *Environment, args ...Object) Object {
+	return quantify(env, "all", args, false)
+}
+
+// quantify implements any and all. It stops at the first element whose
+// truthiness equals stopOn and returns stopOn.
+func quantify(env *Environment, name string, args []Object, stopOn bool) Object {
+	if len(args) != 1 && len(args) != 2 {
+		return newBuiltinError("wrong number of arguments to %s: expected 1 or 2, got %d", name, len(args))
+	}
+	list, ok := args[0].(*List)
+	if !ok {
+		return newBuiltinError("argument to %s must be LIST, got %s", name, args[0].Type())
+	}
+	fn := Object(NULL)
+	if len(args) == 2 {
+		fn = args[1]
+		if fn != NULL && !isCallable(fn) {
+			return newBuiltinError("argument to %s must be FUNCTION, got %s", name, fn.Type())
+		}
+	}
+
+	for _, elem := range list.Elements {
+		value := elem
+		if fn != NULL {
+			value = callFunction(env, fn, elem)
+			if isError(value) {
+				return value
+			}
+		}
+		if isTruthy(value) == stopOn {
+			return nativeBoolToBooleanObject(stopOn)
+		}
+	}
+	return nativeBoolToBooleanObject(!stopOn)
+}
+
+// sortList returns a sorted copy of list. key is NULL, a function, or a
+// field name; reverse must be a Boolean.
+func sortList(env *Environment, name string, list *List, key, reverse Object) Object {
+	descending, ok := reverse.(*Boolean)
+	if !ok {
+		return newBuiltinError("%s reverse must be BOOLEAN, got %s", name, reverse.Type())
+	}
+
+	keys, err := sortKeys(env, name, list, key)
+	if err != nil {
+		return err
+	}
+
+	order := make([]int, len(list.Elements))
+	for i := range order {
+		order[i] = i
+	}
+
+	var compareErr *Error
+	slices.SortStableFunc(order, func(a, b int) int {
+		c, ok := compareObjects(keys[a], keys[b])
+		if !ok && compareErr == nil {
+			compareErr = newBuiltinError("cannot compare %s and %s", keys[a].Type(), keys[b].Type())
+		}
+		if descending.Value {
+			return -c
+		}
+		return c
+	})
+	if compareErr != nil {
+		return compareErr
+	}
+
+	result := make([]Object, len(order))
+	for i, index := range order {
+		result[i] = list.Elements[index]
+	}
+	return &List{Elements: result}
+}
+
+// sortKeys computes the key of each element of list. key is NULL (the
+// element itself), a function applied to the element, or a String naming
+// a field of each object element.
+func sortKeys(env *Environment, name string, list *List, key Object) ([]Object, *Error) {
+	keys := make([]Object, len(list.Elements))
+	for i, elem := range list.Elements {
+		switch k := key.(type) {
+		case *Null:
+			keys[i] = elem
+		case *String:
+			hash, ok := elem.(*Hash)
+			if !ok {
+				return nil, newBuiltinError("%s by field %q requires OBJECT elements, got %s", name, k.Value, elem.Type())
+			}
+			value, ok := hash.Get(k.Value)
+			if !ok {
+				value = NULL
+			}
+			keys[i] = value
+		default:
+			if !isCallable(key) {
+				return nil, newBuiltinError("%s key must be FUNCTION or STRING, got %s", name, key.Type())
+			}
+			value := callFunction(env, key, elem)
+			if err, ok := value.(*Error); ok {
+				return nil, err
+			}
+			keys[i] = value
+		}
+	}
+	return keys, nil
+}
+
+// compareObjects orders two values of the same kind. Integers and floats
+// compare numerically with each other; strings, durations, times and
+// booleans (false before true) compare with their own type. It reports
+// false if the values cannot be ordered.
+func compareObjects(left, right Object) (int, bool) {
+	switch l := left.(type) {
+	case *Integer:
+		switch r := right.(type) {
+		case *Integer:
+			return cmp.Compare(l.Value, r.Value), true
+		case *Float:
+			return cmp.Compare(float64(l.Value), r.Value), true
+		}
+	case *Float:
+		switch r := right.(type) {
+		case *Integer:
+			return cmp.Compare(l.Value, float64(r.Value)), true
+		case *Float:
+			return cmp.Compare(l.Value, r.Value), true
+		}
+	case *String:
+		if r, ok := right.(*String); ok {
+			return cmp.Compare(l.Value, r.Value), true
+		}
+	case *Duration:
+		if r, ok := right.(*Duration); ok {
+			return cmp.Compare(l.Value, r.Value), true
+		}
+	case *Time:
+		if r, ok := right.(*Time); ok {
+			return l.Value.Compare(r.Value), true
+		}
+	case *Boolean:
+		if r, ok := right.(*Boolean); ok {
+			switch {
+			case l.Value == r.Value:
+				return 0, true
+			case r.Value:
+				return -1, true
+			default:
+				return 1, true
+			}
+		}
+	}
+	return 0, false
+}
+
+// groupName returns the object key used for a group_by group.
+func groupName(key Object) string {
+	if s, ok := key.(*String); ok {
+		return s.Value
+	}
+	return key.Inspect()
+}
+
+// listAndCallback validates the (list, fn) arguments shared by map,
+// filter and reduce.
+func listAndCallback(name string, args []Object) (*List, Object, *Error) {
+	if err := checkArgCount(name, args, 2); err != nil {
+		return nil, nil, err
+	}
+	list, ok := args[0].(*List)
+	if !ok {
+		return nil, nil, newBuiltinError("argument to %s must be LIST, got %s", name, args[0].Type())
+	}
+	if !isCallable(args[1]) {
+		return nil, nil, newBuiltinError("argument to %s must be FUNCTION, got %s", name, args[1].Type())
+	}
+	return list, args[1], nil
+}
+
+// isCallable reports whether obj is a user function, builtin or callable
+// namespace.
+func isCallable(obj Object) bool {
+	switch obj := obj.(type) {
+	case *Function, *Builtin:
+		return true
+	case *Namespace:
+		return obj.Call != nil
+	default:
+		return false
+	}
+}
+
+// callFunction invokes a callback from a builtin. Errors raised by a
+// builtin callback are left without a position so that the outer call
+// site is reported.
+func callFunction(env *Environment, fn Object, args ...Object) Object {
+	return applyFunction(env, fn, args, ast.Position{})
+}
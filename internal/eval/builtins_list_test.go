@@ -0,0 +1,150 @@
+package eval
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestBuiltinListFunctions(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`fn double(x) { return x * 2; } map([1, 2, 3], double);`, "[2, 4, 6]"},
+		{`fn double(x) { return x * 2; } map([], double);`, "[]"},
+		{`map([1, "a"], str);`, "[1, a]"},
+		{`fn odd(x) { return x - x / 2 * 2 == 1; } filter([1, 2, 3, 4, 5], odd);`, "[1, 3, 5]"},
+		{`fn add(a, b) { return a + b; } reduce([1, 2, 3, 4], add);`, "10"},
+		{`fn add(a, b) { return a + b; } reduce([1, 2, 3], add, 10);`, "16"},
+		{`fn add(a, b) { return a + b; } reduce([], add, 0);`, "0"},
+		{`fn add(a, b) { return a + b; } reduce(["a", "b"], add, initial: ">");`, ">ab"},
+		{`sort([3, 1, 2]);`, "[1, 2, 3]"},
+		{`sort([3, 1, 2], reverse: true);`, "[3, 2, 1]"},
+		{`sort(["b", "c", "a"]);`, "[a, b, c]"},
+		{`sort([2.5, 1, 2]);`, "[1, 2, 2.5]"},
+		{`sort([5s, 1m, 1ms]);`, "[1ms, 5s, 1m]"},
+		{`fn neg(x) { return -x; } sort([1, 3, 2], key: neg);`, "[3, 2, 1]"},
+		{`fn neg(x) { return -x; } sort([1, 3, 2], neg, true);`, "[1, 2, 3]"},
+		{`sort_by([{n: 2}, {n: 1}], "n");`, "[{n: 1}, {n: 2}]"},
+		{`sort_by([{n: 1}, {n: 2}], "n", reverse: true);`, "[{n: 2}, {n: 1}]"},
+		{`group_by(["bb", "a", "cc", "b"], len);`, "{2: [bb, cc], 1: [a, b]}"},
+		{`fn len_of(s) { return len(s); } sort_by(["ccc", "a", "bb"], len_of);`, "[a, bb, ccc]"},
+		{`unique([1, 2, 1, "a", "a", 3]);`, "[1, 2, a, 3]"},
+		{`unique([1, "1", 1.0, true, null, null]);`, "[1, 1, 1, true, null]"},
+		{`unique([[1, 2], [1, 2], [2, 1]]);`, "[[1, 2], [2, 1]]"},
+		{`unique([{a: 1, b: 2}, {b: 2, a: 1}, {a: 1}]);`, "[{a: 1, b: 2}, {a: 1}]"},
+		{`unique([1s, 1000ms, 0.0, -0.0]);`, "[1s, 0]"},
+		{`fn f() { return 1; } fn g() { return 2; } u = unique([f, f, g, 1, f]); [len(u), u[0](), u[1](), u[2]];`, "[3, 1, 2, 1]"},
+		{`unique([print, print, [print], [print]]);`, "[builtin:print, [builtin:print]]"},
+		{`group_by([{k: [1, "a"]}, {k: [1, "a"]}, {k: [2]}], "k");`, "{[1, a]: [{k: [1, a]}, {k: [1, a]}], [2]: [{k: [2]}]}"},
+		{`flatten([[1, 2], [3, [4]], 5]);`, "[1, 2, 3, [4], 5]"},
+		{`flatten([[1, [2, [3]]]], depth: 5);`, "[1, 2, 3]"},
+		{`flatten([[1]], 0);`, "[[1]]"},
+		{`zip([1, 2, 3], ["a", "b"]);`, "[[1, a], [2, b]]"},
+		{`zip([1], [2], [3]);`, "[[1, 2, 3]]"},
+		{`chunk([1, 2, 3, 4, 5], 2);`, "[[1, 2], [3, 4], [5]]"},
+		{`chunk([], 3);`, "[]"},
+		{`sum([1, 2, 3]);`, "6"},
+		{`sum([]);`, "0"},
+		{`sum([1, 2.5]);`, "3.5"},
+		{`sum([1m, 30s]);`, "1m30s"},
+		{`min([3, 1, 2]);`, "1"},
+		{`max([3, 1, 2]);`, "3"},
+		{`min(3, 1.5, 2);`, "1.5"},
+		{`max("a", "c", "b");`, "c"},
+		{`any([false, null, 1]);`, "true"},
+		{`any([]);`, "false"},
+		{`all([1, "a", true]);`, "true"},
+		{`all([1, null]);`, "false"},
+		{`all([]);`, "true"},
+		{`fn big(x) { return x > 2; } any([1, 2, 3], big);`, "true"},
+		{`fn double(x) { return x * 2; } map(callback: double, list: [1]);`, "[2]"},
+		{`fn big(x) { return x > 2; } all([1, 2, 3], predicate: big);`, "false"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			var stdout bytes.Buffer
+			evaluated := testEvalWithBuiltins(tt.input, &stdout)
+			if evaluated == nil {
+				t.Fatalf("evaluated to nil")
+			}
+			if evaluated.Inspect() != tt.expected {
+				t.Errorf("wrong result. got=%s, want=%s", evaluated.Inspect(), tt.expected)
+			}
+		})
+	}
+}
+
+func TestBuiltinGroupBy(t *testing.T) {
+	input := `
+items = [
+    {pk: "a", n: 1},
+    {pk: "b", n: 2},
+    {pk: "a", n: 3}
+];
+groups = group_by(items, "pk");
+[len(groups), len(groups.a), len(groups.b), groups.a[1].n];
+`
+	var stdout bytes.Buffer
+	evaluated := testEvalWithBuiltins(input, &stdout)
+	if evaluated.Inspect() != "[2, 2, 1, 3]" {
+		t.Errorf("wrong result. got=%s", evaluated.Inspect())
+	}
+}
+
+func TestBuiltinListCallbackError(t *testing.T) {
+	var stdout bytes.Buffer
+	evaluated := testEvalWithBuiltins("fn bad(x) { return x + \"s\"; }\nmap([1], bad);", &stdout)
+	errObj, ok := evaluated.(*Error)
+	if !ok {
+		t.Fatalf("object is not Error. got=%T (%+v)", evaluated, evaluated)
+	}
+	if errObj.Message != "type mismatch: INTEGER + STRING" {
+		t.Errorf("wrong message. got=%q", errObj.Message)
+	}
+	if errObj.Line != 1 {
+		t.Errorf("expected error inside callback on line 1, got line %d", errObj.Line)
+	}
+}
+
+func TestBuiltinListErrors(t *testing.T) {
+	tests := []struct {
+		input           string
+		expectedMessage string
+	}{
+		{`map([1]);`, "wrong number of arguments to map: expected 2, got 1"},
+		{`map(1, str);`, "argument to map must be LIST, got INTEGER"},
+		{`map([1], 1);`, "argument to map must be FUNCTION, got INTEGER"},
+		{`fn two(a, b) { return a; } map([1], two);`, "wrong number of arguments: expected 2, got 1"},
+		{`fn add(a, b) { return a + b; } reduce([], add);`, "reduce of empty list with no initial value"},
+		{`sort([1, "a"]);`, "cannot compare STRING and INTEGER"},
+		{`sort([1], reverse: 1);`, "sort reverse must be BOOLEAN, got INTEGER"},
+		{`sort([1], key: 1);`, "sort key must be FUNCTION or STRING, got INTEGER"},
+		{`sort_by([1], "n");`, `sort_by by field "n" requires OBJECT elements, got INTEGER`},
+		{`sort_by([1], reverse: true);`, "sort_by requires a key"},
+		{`group_by([{k: print}], "k");`, "group_by key must be hashable, got BUILTIN"},
+		{`group_by([{k: 1}, {k: "1"}], "k");`, `group_by keys of different types both print as "1"`},
+		{`flatten([1], -1);`, "flatten depth must not be negative, got -1"},
+		{`zip([1]);`, "wrong number of arguments to zip: expected at least 2, got 1"},
+		{`zip([1], 2);`, "argument 2 to zip must be LIST, got INTEGER"},
+		{`chunk([1], 0);`, "chunk size must be positive, got 0"},
+		{`sum(["a"]);`, "sum requires numbers or durations, got STRING"},
+		{`sum([1, 1s]);`, "sum requires numbers or durations, got INTEGER and DURATION"},
+		{`sum([9223372036854775807, 1]);`, "integer overflow: 9223372036854775807 + 1"},
+		{`min([]);`, "min of empty list"},
+		{`max(1);`, "argument to max must be LIST, got INTEGER"},
+		{`max([1, "a"]);`, "cannot compare STRING and INTEGER"},
+		{`any([1], 2);`, "argument to any must be FUNCTION, got INTEGER"},
+		{`sort([1], nope: true);`, `unknown argument "nope" to sort`},
+		{`zip(a: [1], b: [2]);`, `unknown argument "a" to zip`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			var stdout bytes.Buffer
+			evaluated := testEvalWithBuiltins(tt.input, &stdout)
+			testErrorObject(t, evaluated, tt.expectedMessage)
+		})
+	}
+}
@@ -0,0 +1,134 @@
+package eval
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"os"
+)
+
+// NewLogger returns the logger used by the log builtins. Records below
+// level are dropped. Text records are logfmt lines without a timestamp,
+// for reading on a terminal; JSON records are one object per line with
+// a timestamp, for log collectors.
+func NewLogger(w io.Writer, level slog.Level, json bool) *slog.Logger {
+	if json {
+		return slog.New(slog.NewJSONHandler(w, &slog.HandlerOptions{Level: level}))
+	}
+	return slog.New(slog.NewTextHandler(w, &slog.HandlerOptions{
+		Level: level,
+		ReplaceAttr: func(groups []string, attr slog.Attr) slog.Attr {
+			if len(groups) == 0 && attr.Key == slog.TimeKey {
+				return slog.Attr{}
+			}
+			return attr
+		},
+	}))
+}
+
+// defaultLogger is used by environments without a logger: text records
+// of level info and above on stderr.
+var defaultLogger = NewLogger(os.Stderr, slog.LevelInfo, false)
+
+// builtinLogDebug logs a debug record.
+// Returns NULL.
+func builtinLogDebug(env *Environment, args ...Object) Object {
+	return writeLog(env, "log.debug", slog.LevelDebug, args)
+}
+
+// builtinLogInfo logs an info record.
+// Returns NULL.
+func builtinLogInfo(env *Environment, args ...Object) Object {
+	return writeLog(env, "log.info", slog.LevelInfo, args)
+}
+
+// builtinLogWarn logs a warning record.
+// Returns NULL.
+func builtinLogWarn(env *Environment, args ...Object) Object {
+	return writeLog(env, "log.warn", slog.LevelWarn, args)
+}
+
+// builtinLogError logs an error record. It does not stop the script.
+// Returns NULL.
+func builtinLogError(env *Environment, args ...Object) Object {
+	return writeLog(env, "log.error", slog.LevelError, args)
+}
+
+// writeLog implements the log builtins. The record holds the message,
+// the line of the call and the pairs of the optional fields object.
+func writeLog(env *Environment, name string, level slog.Level, args []Object) Object {
+	if len(args) != 1 && len(args) != 2 {
+		return newBuiltinError("wrong number of arguments to %s: expected 1 or 2, got %d", name, len(args))
+	}
+
+	var fields *Hash
+	if len(args) == 2 && args[1] != NULL {
+		var ok bool
+		fields, ok = args[1].(*Hash)
+		if !ok {
+			return argumentTypeError(name, "fields", HASH_OBJ, args[1])
+		}
+	}
+
+	logger := env.Logger()
+	ctx := context.Background()
+	if !logger.Enabled(ctx, level) {
+		return NULL
+	}
+
+	message := args[0].Inspect()
+	attrs := []slog.Attr{}
+	if line := env.CallPosition().Line; line > 0 {
+		attrs = append(attrs, slog.Int("line", line))
+	}
+	if fields != nil {
+		for _, pair := range fields.Pairs() {
+			attrs = append(attrs, logAttr(pair.Key, pair.Value))
+		}
+	}
+	logger.LogAttrs(ctx, level, message, attrs...)
+	return NULL
+}
+
+// logAttr converts a field to a log attribute. Scalars keep their type,
+// so JSON records hold numbers and booleans; durations are written as
+// in scripts, such as 1m30s.
+func logAttr(key string, value Object) slog.Attr {
+	switch value := value.(type) {
+	case *Integer:
+		return slog.Int64(key, value.Value)
+	case *Float:
+		return slog.Float64(key, value.Value)
+	case *Boolean:
+		return slog.Bool(key, value.Value)
+	case *String:
+		return slog.String(key, value.Value)
+	case *Time:
+		return slog.Time(key, value.Value)
+	case *Null:
+		return slog.Any(key, nil)
+	default:
+		return slog.Any(key, logObject{value})
+	}
+}
+
+// logObject wraps a composite field value. JSON records encode it as
+// json.stringify does; text records use its printed form.
+type logObject struct {
+	Object
+}
+
+// MarshalJSON encodes the value as JSON.
+func (o logObject) MarshalJSON() ([]byte, error) {
+	text, err := encodeJSON(o.Object, "")
+	if err != nil {
+		return nil, errors.New(err.Message)
+	}
+	return []byte(text), nil
+}
+
+// MarshalText returns the printed form of the value.
+func (o logObject) MarshalText() ([]byte, error) {
+	return []byte(o.Inspect()), nil
+}
@@ -0,0 +1,134 @@
+package eval
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"testing"
+
+	"github.com/boattime/awsl/internal/lexer"
+	"github.com/boattime/awsl/internal/parser"
+)
+
+// testEvalWithLogger evaluates input with the log builtins writing to
+// logs.
+func testEvalWithLogger(input string, logs *bytes.Buffer, level slog.Level, json bool) Object {
+	l := lexer.New(input)
+	p := parser.New(l)
+	program := p.ParseProgram()
+	var stdout bytes.Buffer
+	env := NewEnvironment(&stdout)
+	env.SetLogger(NewLogger(logs, level, json))
+	RegisterBuiltins(env)
+	return Eval(program, env)
+}
+
+func TestBuiltinLogText(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`log.info("started");`, "level=INFO msg=started line=1\n"},
+		{`log.warn("slow", {ms: 1500, op: "api"});`, "level=WARN msg=slow line=1 ms=1500 op=api\n"},
+		{`log.error("failed", {err: null, retry: false});`, "level=ERROR msg=failed line=1 err=<nil> retry=false\n"},
+		{`log.info(42);`, "level=INFO msg=42 line=1\n"},
+		{`log.info("ids", {ids: [1, 2]});`, "level=INFO msg=ids line=1 ids=\"[1, 2]\"\n"},
+		{`log.info("took", {d: 90s});`, "level=INFO msg=took line=1 d=1m30s\n"},
+		{`log.info("none", null);`, "level=INFO msg=none line=1\n"},
+		{"x = 1;\n\nlog.info(\"third\");", "level=INFO msg=third line=3\n"},
+		{`log.debug("hidden");`, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			var logs bytes.Buffer
+			result := testEvalWithLogger(tt.input, &logs, slog.LevelInfo, false)
+			testNullObject(t, result)
+			if logs.String() != tt.expected {
+				t.Errorf("wrong log output. got=%q, want=%q", logs.String(), tt.expected)
+			}
+		})
+	}
+}
+
+func TestBuiltinLogLevels(t *testing.T) {
+	input := `log.debug("d"); log.info("i"); log.warn("w"); log.error("e");`
+	tests := []struct {
+		level    slog.Level
+		expected string
+	}{
+		{slog.LevelDebug, "level=DEBUG msg=d line=1\nlevel=INFO msg=i line=1\nlevel=WARN msg=w line=1\nlevel=ERROR msg=e line=1\n"},
+		{slog.LevelWarn, "level=WARN msg=w line=1\nlevel=ERROR msg=e line=1\n"},
+		{slog.LevelError, "level=ERROR msg=e line=1\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.level.String(), func(t *testing.T) {
+			var logs bytes.Buffer
+			testEvalWithLogger(input, &logs, tt.level, false)
+			if logs.String() != tt.expected {
+				t.Errorf("wrong log output. got=%q, want=%q", logs.String(), tt.expected)
+			}
+		})
+	}
+}
+
+func TestBuiltinLogCallbackLine(t *testing.T) {
+	var logs bytes.Buffer
+	input := "fn note(x) {\n  log.info(\"item\", {x: x});\n  return x;\n}\nmap([1], note);\nmap([2], log.info);"
+	testEvalWithLogger(input, &logs, slog.LevelInfo, false)
+	expected := "level=INFO msg=item line=2 x=1\nlevel=INFO msg=2 line=6\n"
+	if logs.String() != expected {
+		t.Errorf("wrong log output. got=%q, want=%q", logs.String(), expected)
+	}
+}
+
+func TestBuiltinLogJSON(t *testing.T) {
+	var logs bytes.Buffer
+	input := `log.info("deployed", {stage: "prod", count: 3, ratio: 0.5, ok: true, tags: ["a"], meta: {n: 1}});`
+	testEvalWithLogger(input, &logs, slog.LevelInfo, true)
+
+	var record map[string]any
+	if err := json.Unmarshal(logs.Bytes(), &record); err != nil {
+		t.Fatalf("log output is not JSON: %v (%q)", err, logs.String())
+	}
+	expected := map[string]any{
+		"level": "INFO", "msg": "deployed", "line": 1.0, "stage": "prod", "count": 3.0,
+		"ratio": 0.5, "ok": true,
+	}
+	for key, want := range expected {
+		if record[key] != want {
+			t.Errorf("wrong %s. got=%v, want=%v", key, record[key], want)
+		}
+	}
+	if _, ok := record["time"]; !ok {
+		t.Errorf("expected a time field, got %v", record)
+	}
+	if tags, ok := record["tags"].([]any); !ok || len(tags) != 1 || tags[0] != "a" {
+		t.Errorf("wrong tags. got=%v", record["tags"])
+	}
+	if meta, ok := record["meta"].(map[string]any); !ok || meta["n"] != 1.0 {
+		t.Errorf("wrong meta. got=%v", record["meta"])
+	}
+}
+
+func TestBuiltinLogErrors(t *testing.T) {
+	tests := []struct {
+		input           string
+		expectedMessage string
+	}{
+		{`log.info();`, "wrong number of arguments to log.info: expected 1 or 2, got 0"},
+		{`log.warn("a", {}, 1);`, "wrong number of arguments to log.warn: expected 1 or 2, got 3"},
+		{`log.error("a", [1]);`, "log.error fields must be HASH, got LIST"},
+		{`log.debug("a", "b");`, "log.debug fields must be HASH, got STRING"},
+		{`log.trace("a");`, "undefined member: log.trace"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			var logs bytes.Buffer
+			evaluated := testEvalWithLogger(tt.input, &logs, slog.LevelInfo, false)
+			testErrorObject(t, evaluated, tt.expectedMessage)
+		})
+	}
+}
@@ -0,0 +1,476 @@
+package eval
+
+import (
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// builtinAbs returns the absolute value of a number or duration.
+// Returns a value of the argument's type.
+func builtinAbs(env *Environment, args ...Object) Object {
+	if err := checkArgCount("abs", args, 1); err != nil {
+		return err
+	}
+
+	switch arg := args[0].(type) {
+	case *Integer:
+		if arg.Value == math.MinInt64 {
+			return newBuiltinError("abs: integer overflow")
+		}
+		if arg.Value < 0 {
+			return &Integer{Value: -arg.Value}
+		}
+		return arg
+	case *Float:
+		return &Float{Value: math.Abs(arg.Value)}
+	case *Duration:
+		if arg.Value == math.MinInt64 {
+			return newBuiltinError("abs: duration overflow")
+		}
+		if arg.Value < 0 {
+			return &Duration{Value: -arg.Value}
+		}
+		return arg
+	default:
+		return newBuiltinError("argument to abs must be INTEGER, FLOAT or DURATION, got %s", arg.Type())
+	}
+}
+
+// builtinFloor rounds a number down to the nearest integer.
+// Returns Integer.
+func builtinFloor(env *Environment, args ...Object) Object {
+	return roundToInteger("floor", args, math.Floor)
+}
+
+// builtinCeil rounds a number up to the nearest integer.
+// Returns Integer.
+func builtinCeil(env *Environment, args ...Object) Object {
+	return roundToInteger("ceil", args, math.Ceil)
+}
+
+// roundToInteger implements floor and ceil, and round without digits.
+func roundToInteger(name string, args []Object, round func(float64) float64) Object {
+	if err := checkArgCount(name, args, 1); err != nil {
+		return err
+	}
+
+	switch arg := args[0].(type) {
+	case *Integer:
+		return arg
+	case *Float:
+		return floatToInteger(name, round(arg.Value))
+	default:
+		return argumentTypeError(name, "", "INTEGER or FLOAT", arg)
+	}
+}
+
+// builtinRound rounds a number to the nearest integer, or to digits
+// decimal places. Halves round away from zero. Negative digits round to
+// tens, hundreds and so on.
+// Returns Integer without digits; otherwise a value of the number's type.
+func builtinRound(env *Environment, args ...Object) Object {
+	if len(args) != 1 && len(args) != 2 {
+		return newBuiltinError("wrong number of arguments to round: expected 1 or 2, got %d", len(args))
+	}
+	if len(args) == 1 || args[1] == NULL {
+		return roundToInteger("round", args[:1], math.Round)
+	}
+
+	digits, err := integerArgument("round", "digits", args[1])
+	if err != nil {
+		return err
+	}
+
+	switch arg := args[0].(type) {
+	case *Integer:
+		return roundInteger(arg, digits)
+	case *Float:
+		return &Float{Value: roundFloat(arg.Value, digits)}
+	default:
+		return argumentTypeError("round", "", "INTEGER or FLOAT", arg)
+	}
+}
+
+// roundFloat rounds x to digits decimal places, halves away from zero.
+func roundFloat(x float64, digits int64) float64 {
+	if x == 0 || math.IsNaN(x) || math.IsInf(x, 0) || digits > 308 {
+		return x
+	}
+	if digits < -308 {
+		return math.Copysign(0, x)
+	}
+	if digits < 0 {
+		scale := math.Pow10(int(-digits))
+		return math.Round(x/scale) * scale
+	}
+	scale := math.Pow10(int(digits))
+	scaled := x * scale
+	if math.IsInf(scaled, 0) {
+		return x
+	}
+	return math.Round(scaled) / scale
+}
+
+// roundInteger rounds n to a multiple of 10^-digits. Integers have no
+// decimal places, so non-negative digits leave n unchanged.
+func roundInteger(n *Integer, digits int64) Object {
+	if digits >= 0 {
+		return n
+	}
+	if digits < -18 {
+		return &Integer{Value: 0}
+	}
+
+	scale := int64(math.Pow10(int(-digits)))
+	quotient, remainder := n.Value/scale, n.Value%scale
+	if remainder >= scale-remainder {
+		quotient++
+	} else if -remainder >= scale+remainder {
+		quotient--
+	}
+	if quotient > math.MaxInt64/scale || quotient < math.MinInt64/scale {
+		return newBuiltinError("round: integer overflow")
+	}
+	return &Integer{Value: quotient * scale}
+}
+
+// builtinSqrt returns the square root of a non-negative number.
+// Returns Float.
+func builtinSqrt(env *Environment, args ...Object) Object {
+	if err := checkArgCount("sqrt", args, 1); err != nil {
+		return err
+	}
+	x, err := numberArgument("sqrt", "", args[0])
+	if err != nil {
+		return err
+	}
+	if x < 0 {
+		return newBuiltinError("sqrt of negative number %s", args[0].Inspect())
+	}
+	return &Float{Value: math.Sqrt(x)}
+}
+
+// builtinPow raises base to a power. Integer powers of integers with a
+// non-negative exponent stay integers and fail on overflow.
+// Returns Integer or Float.
+func builtinPow(env *Environment, args ...Object) Object {
+	if err := checkArgCount("pow", args, 2); err != nil {
+		return err
+	}
+
+	base, baseIsInt := args[0].(*Integer)
+	exp, expIsInt := args[1].(*Integer)
+	if baseIsInt && expIsInt && exp.Value >= 0 {
+		result, factor := int64(1), base.Value
+		for e := exp.Value; e > 0; e >>= 1 {
+			ok := true
+			if e&1 == 1 {
+				result, ok = multiplyInt64(result, factor)
+			}
+			if ok && e > 1 {
+				factor, ok = multiplyInt64(factor, factor)
+			}
+			if !ok {
+				return newBuiltinError("integer overflow: pow(%d, %d)", base.Value, exp.Value)
+			}
+		}
+		return &Integer{Value: result}
+	}
+
+	x, err := numberArgument("pow", "base", args[0])
+	if err != nil {
+		return err
+	}
+	y, err := numberArgument("pow", "exponent", args[1])
+	if err != nil {
+		return err
+	}
+	return &Float{Value: math.Pow(x, y)}
+}
+
+// builtinLog returns the natural logarithm of a positive number, or its
+// logarithm in base.
+// Returns Float.
+func builtinLog(env *Environment, args ...Object) Object {
+	if len(args) != 1 && len(args) != 2 {
+		return newBuiltinError("wrong number of arguments to log: expected 1 or 2, got %d", len(args))
+	}
+	x, err := numberArgument("log", "", args[0])
+	if err != nil {
+		return err
+	}
+	if x <= 0 {
+		return newBuiltinError("log of non-positive number %s", args[0].Inspect())
+	}
+	if len(args) == 1 || args[1] == NULL {
+		return &Float{Value: math.Log(x)}
+	}
+
+	base, err := numberArgument("log", "base", args[1])
+	if err != nil {
+		return err
+	}
+	switch base {
+	case 2:
+		return &Float{Value: math.Log2(x)}
+	case 10:
+		return &Float{Value: math.Log10(x)}
+	}
+	if base <= 0 || base == 1 {
+		return newBuiltinError("log base must be positive and not 1, got %s", args[1].Inspect())
+	}
+	return &Float{Value: math.Log(x) / math.Log(base)}
+}
+
+// builtinClamp limits a value to the range [low, high]. Any values that
+// min and max can compare are accepted.
+// Returns value, low or high.
+func builtinClamp(env *Environment, args ...Object) Object {
+	if err := checkArgCount("clamp", args, 3); err != nil {
+		return err
+	}
+	value, low, high := args[0], args[1], args[2]
+
+	order, ok := compareObjects(low, high)
+	if !ok {
+		return newBuiltinError("cannot compare %s and %s", low.Type(), high.Type())
+	}
+	if order > 0 {
+		return newBuiltinError("clamp low must not be greater than high, got %s and %s", low.Inspect(), high.Inspect())
+	}
+
+	if c, ok := compareObjects(value, low); !ok {
+		return newBuiltinError("cannot compare %s and %s", value.Type(), low.Type())
+	} else if c < 0 {
+		return low
+	}
+	if c, ok := compareObjects(value, high); !ok {
+		return newBuiltinError("cannot compare %s and %s", value.Type(), high.Type())
+	} else if c > 0 {
+		return high
+	}
+	return value
+}
+
+// builtinMean returns the arithmetic mean of a list of numbers or of
+// durations.
+// Returns Float, or Duration for durations.
+func builtinMean(env *Environment, args ...Object) Object {
+	if err := checkArgCount("mean", args, 1); err != nil {
+		return err
+	}
+	values, durations, err := statisticsValues("mean", args[0])
+	if err != nil {
+		return err
+	}
+
+	total := 0.0
+	for _, value := range values {
+		total += value
+	}
+	return statisticsResult(total/float64(len(values)), durations)
+}
+
+// builtinMedian returns the middle value of a list of numbers or of
+// durations, the mean of the two middle values for an even count.
+// Returns Float, or Duration for durations.
+func builtinMedian(env *Environment, args ...Object) Object {
+	if err := checkArgCount("median", args, 1); err != nil {
+		return err
+	}
+	values, durations, err := statisticsValues("median", args[0])
+	if err != nil {
+		return err
+	}
+	return statisticsResult(percentile(values, 50), durations)
+}
+
+// builtinPercentile returns the p-th percentile (0 to 100) of a list of
+// numbers or of durations, interpolating linearly between the closest
+// ranks.
+// Returns Float, or Duration for durations.
+func builtinPercentile(env *Environment, args ...Object) Object {
+	if err := checkArgCount("percentile", args, 2); err != nil {
+		return err
+	}
+	values, durations, err := statisticsValues("percentile", args[0])
+	if err != nil {
+		return err
+	}
+	p, err := numberArgument("percentile", "p", args[1])
+	if err != nil {
+		return err
+	}
+	if !(p >= 0 && p <= 100) {
+		return newBuiltinError("percentile p must be between 0 and 100, got %s", args[1].Inspect())
+	}
+	return statisticsResult(percentile(values, p), durations)
+}
+
+// percentile returns the p-th percentile of values, which it sorts.
+func percentile(values []float64, p float64) float64 {
+	slices.Sort(values)
+	rank := p / 100 * float64(len(values)-1)
+	lower := int(math.Floor(rank))
+	if lower == len(values)-1 {
+		return values[lower]
+	}
+	fraction := rank - float64(lower)
+	return values[lower] + fraction*(values[lower+1]-values[lower])
+}
+
+// statisticsValues unwraps the list argument of mean, median and
+// percentile. The elements must all be numbers or all be durations;
+// durations are returned in nanoseconds.
+func statisticsValues(name string, arg Object) ([]float64, bool, *Error) {
+	list, ok := arg.(*List)
+	if !ok {
+		return nil, false, argumentTypeError(name, "", LIST_OBJ, arg)
+	}
+	if len(list.Elements) == 0 {
+		return nil, false, newBuiltinError("%s of empty list", name)
+	}
+
+	_, durations := list.Elements[0].(*Duration)
+	values := make([]float64, len(list.Elements))
+	for i, elem := range list.Elements {
+		switch elem := elem.(type) {
+		case *Integer:
+			if !durations {
+				values[i] = float64(elem.Value)
+				continue
+			}
+		case *Float:
+			if !durations {
+				values[i] = elem.Value
+				continue
+			}
+		case *Duration:
+			if durations {
+				values[i] = float64(elem.Value)
+				continue
+			}
+		}
+		return nil, false, newBuiltinError("%s requires all numbers or all durations, got %s and %s",
+			name, list.Elements[0].Type(), elem.Type())
+	}
+	return values, durations, nil
+}
+
+// statisticsResult wraps a computed statistic, converting nanoseconds
+// back to a Duration for lists of durations.
+func statisticsResult(value float64, durations bool) Object {
+	if durations {
+		return &Duration{Value: time.Duration(math.Round(value))}
+	}
+	return &Float{Value: value}
+}
+
+// builtinFormatNumber formats a number with a separator between groups
+// of thousands and, if decimals is given, exactly that many decimal
+// places.
+// Returns String.
+func builtinFormatNumber(env *Environment, args ...Object) Object {
+	if len(args) < 1 || len(args) > 3 {
+		return newBuiltinError("wrong number of arguments to format_number: expected 1 to 3, got %d", len(args))
+	}
+
+	decimals := int64(-1)
+	if len(args) > 1 && args[1] != NULL {
+		var err *Error
+		decimals, err = integerArgument("format_number", "decimals", args[1])
+		if err != nil {
+			return err
+		}
+		if decimals < 0 || decimals > 100 {
+			return newBuiltinError("format_number decimals must be between 0 and 100, got %d", decimals)
+		}
+	}
+
+	separator := ","
+	if len(args) > 2 && args[2] != NULL {
+		var err *Error
+		separator, err = stringArgument("format_number", "separator", args[2])
+		if err != nil {
+			return err
+		}
+	}
+
+	var text string
+	switch arg := args[0].(type) {
+	case *Integer:
+		text = strconv.FormatInt(arg.Value, 10)
+		if decimals > 0 {
+			text += "." + strings.Repeat("0", int(decimals))
+		}
+	case *Float:
+		if math.IsNaN(arg.Value) || math.IsInf(arg.Value, 0) {
+			return newBuiltinError("format_number: cannot format %s", arg.Inspect())
+		}
+		if decimals < 0 {
+			text = strconv.FormatFloat(arg.Value, 'f', -1, 64)
+		} else {
+			text = strconv.FormatFloat(roundFloat(arg.Value, decimals), 'f', int(decimals), 64)
+		}
+	default:
+		return argumentTypeError("format_number", "", "INTEGER or FLOAT", arg)
+	}
+	return &String{Value: groupThousands(text, separator)}
+}
+
+// groupThousands inserts separator between groups of three digits in the
+// integer part of a formatted number.
+func groupThousands(text, separator string) string {
+	sign := ""
+	if strings.HasPrefix(text, "-") {
+		sign, text = "-", text[1:]
+	}
+	whole, fraction, hasFraction := strings.Cut(text, ".")
+
+	var out strings.Builder
+	out.WriteString(sign)
+	for i, digit := range whole {
+		if i > 0 && (len(whole)-i)%3 == 0 {
+			out.WriteString(separator)
+		}
+		out.WriteRune(digit)
+	}
+	if hasFraction {
+		out.WriteString(".")
+		out.WriteString(fraction)
+	}
+	return out.String()
+}
+
+// floatToInteger converts an integral float to an Integer, failing if it
+// is out of range.
+func floatToInteger(name string, value float64) Object {
+	if math.IsNaN(value) || value >= math.MaxInt64 || value < math.MinInt64 {
+		return newBuiltinError("%s: %s is out of range for int", name, (&Float{Value: value}).Inspect())
+	}
+	return &Integer{Value: int64(value)}
+}
+
+// numberArgument unwraps an Integer or Float argument as a float64.
+func numberArgument(name, param string, arg Object) (float64, *Error) {
+	switch arg := arg.(type) {
+	case *Integer:
+		return float64(arg.Value), nil
+	case *Float:
+		return arg.Value, nil
+	default:
+		return 0, argumentTypeError(name, param, "INTEGER or FLOAT", arg)
+	}
+}
+
+// multiplyInt64 returns a * b and whether it fits in an int64.
+func multiplyInt64(a, b int64) (int64, bool) {
+	product := a * b
+	if a != 0 && (product/a != b || (a == -1 && b == math.MinInt64)) {
+		return 0, false
+	}
+	return product, true
+}
@@ -0,0 +1,129 @@
+package eval
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestBuiltinMathFunctions(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`abs(-5);`, "5"},
+		{`abs(5);`, "5"},
+		{`abs(-2.5);`, "2.5"},
+		{`abs(-90s);`, "1m30s"},
+		{`floor(2.7);`, "2"},
+		{`floor(-2.2);`, "-3"},
+		{`floor(4);`, "4"},
+		{`ceil(2.1);`, "3"},
+		{`ceil(-2.7);`, "-2"},
+		{`round(2.5);`, "3"},
+		{`round(-2.5);`, "-3"},
+		{`round(2.4);`, "2"},
+		{`round(3.14159, 2);`, "3.14"},
+		{`round(0.125, 2);`, "0.13"},
+		{`round(1234.5, -2);`, "1200"},
+		{`round(1250, -2);`, "1300"},
+		{`round(-1250, -2);`, "-1300"},
+		{`round(1249, -2);`, "1200"},
+		{`round(42, 2);`, "42"},
+		{`round(42, -30);`, "0"},
+		{`type(round(2.5));`, "int"},
+		{`type(round(2.5, 0));`, "float"},
+		{`sqrt(16);`, "4"},
+		{`sqrt(2.25);`, "1.5"},
+		{`pow(2, 10);`, "1024"},
+		{`pow(-3, 3);`, "-27"},
+		{`pow(7, 0);`, "1"},
+		{`pow(2, -1);`, "0.5"},
+		{`pow(2.5, 2);`, "6.25"},
+		{`pow(-1, 9223372036854775807);`, "-1"},
+		{`type(pow(2, 3));`, "int"},
+		{`log(1);`, "0"},
+		{`log(1024, 2);`, "10"},
+		{`log(1000, 10);`, "3"},
+		{`round(log(81, 3), 9);`, "4"},
+		{`map([1, 8], log);`, "[0, 2.0794415416798357]"},
+		{`clamp(15, 0, 10);`, "10"},
+		{`clamp(-5, 0, 10);`, "0"},
+		{`clamp(2.5, 0, 10);`, "2.5"},
+		{`clamp(5 min, 1s, 1 min);`, "1m"},
+		{`mean([1, 2, 3, 4]);`, "2.5"},
+		{`mean([2.5]);`, "2.5"},
+		{`mean([1s, 2s]);`, "1.5s"},
+		{`median([5, 1, 3]);`, "3"},
+		{`median([4, 1, 3, 2]);`, "2.5"},
+		{`median([300ms, 100ms, 200ms, 10s]);`, "250ms"},
+		{`percentile([1, 2, 3, 4, 5], 0);`, "1"},
+		{`percentile([1, 2, 3, 4, 5], 100);`, "5"},
+		{`percentile([1, 2, 3, 4, 5], 90);`, "4.6"},
+		{`percentile([10, 20], 25);`, "12.5"},
+		{`percentile([42], 99);`, "42"},
+		{`xs = [3, 1, 2]; percentile(xs, 50); xs;`, "[3, 1, 2]"},
+		{`format_number(1234567);`, "1,234,567"},
+		{`format_number(-1234567.891, 2);`, "-1,234,567.89"},
+		{`format_number(1234.5);`, "1,234.5"},
+		{`format_number(999, 2);`, "999.00"},
+		{`format_number(0.125, 2);`, "0.13"},
+		{`format_number(1e6, 0);`, "1,000,000"},
+		{`format_number(1234567, separator: " ");`, "1 234 567"},
+		{`format_number(1234567.5, 1, "");`, "1234567.5"},
+		{`format_number(-12);`, "-12"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			var stdout bytes.Buffer
+			evaluated := testEvalWithBuiltins(tt.input, &stdout)
+			if evaluated.Inspect() != tt.expected {
+				t.Errorf("wrong result. got=%q, want=%q", evaluated.Inspect(), tt.expected)
+			}
+		})
+	}
+}
+
+func TestBuiltinMathErrors(t *testing.T) {
+	tests := []struct {
+		input           string
+		expectedMessage string
+	}{
+		{`abs("5");`, "argument to abs must be INTEGER, FLOAT or DURATION, got STRING"},
+		{`abs(-9223372036854775807 - 1);`, "abs: integer overflow"},
+		{`floor("2.5");`, "argument to floor must be INTEGER or FLOAT, got STRING"},
+		{`ceil(1e300);`, "ceil: 1e+300 is out of range for int"},
+		{`round();`, "wrong number of arguments to round: expected 1 or 2, got 0"},
+		{`round(2.5, 1.5);`, "round digits must be INTEGER, got FLOAT"},
+		{`round(9223372036854775807, -1);`, "round: integer overflow"},
+		{`sqrt(-4);`, "sqrt of negative number -4"},
+		{`sqrt(null);`, "argument to sqrt must be INTEGER or FLOAT, got NULL"},
+		{`pow(2, 63);`, "integer overflow: pow(2, 63)"},
+		{`pow(10, 100);`, "integer overflow: pow(10, 100)"},
+		{`pow(2, "3");`, "pow exponent must be INTEGER or FLOAT, got STRING"},
+		{`log(0);`, "log of non-positive number 0"},
+		{`log(8, 1);`, "log base must be positive and not 1, got 1"},
+		{`log(8, -2);`, "log base must be positive and not 1, got -2"},
+		{`clamp(5, 10, 0);`, "clamp low must not be greater than high, got 10 and 0"},
+		{`clamp("a", 0, 10);`, "cannot compare STRING and INTEGER"},
+		{`clamp(1, 0, "z");`, "cannot compare INTEGER and STRING"},
+		{`mean([]);`, "mean of empty list"},
+		{`mean(5);`, "argument to mean must be LIST, got INTEGER"},
+		{`median([1, "2"]);`, "median requires all numbers or all durations, got INTEGER and STRING"},
+		{`median([1s, 2]);`, "median requires all numbers or all durations, got DURATION and INTEGER"},
+		{`percentile([1, 2], 101);`, "percentile p must be between 0 and 100, got 101"},
+		{`percentile([1, 2], -1);`, "percentile p must be between 0 and 100, got -1"},
+		{`format_number("1");`, "argument to format_number must be INTEGER or FLOAT, got STRING"},
+		{`format_number(1.5, -1);`, "format_number decimals must be between 0 and 100, got -1"},
+		{`format_number(1, separator: 1);`, "format_number separator must be STRING, got INTEGER"},
+		{`format_number(pow(-8, 0.5));`, "format_number: cannot format NaN"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			var stdout bytes.Buffer
+			evaluated := testEvalWithBuiltins(tt.input, &stdout)
+			testErrorObject(t, evaluated, tt.expectedMessage)
+		})
+	}
+}
@@ -0,0 +1,242 @@
+package eval
+
+import (
+	"errors"
+	"math"
+	"regexp"
+	"regexp/syntax"
+	"strings"
+)
+
+// builtinRegexCompile compiles a pattern once so it can be reused by the
+// other re functions and =~.
+// Returns Regex.
+func builtinRegexCompile(env *Environment, args ...Object) Object {
+	if err := checkArgCount("re.compile", args, 1); err != nil {
+		return err
+	}
+	if _, ok := args[0].(*String); !ok {
+		return argumentTypeError("re.compile", "", STRING_OBJ, args[0])
+	}
+	re, err := regexArgument("re.compile", args[0])
+	if err != nil {
+		return err
+	}
+	return &Regex{Value: re}
+}
+
+// builtinRegexMatch finds the first match of a pattern anywhere in a
+// string. Anchor the pattern with ^ and $ to match the whole string.
+// Returns the match (see regexMatchValue), or NULL if there is none.
+func builtinRegexMatch(env *Environment, args ...Object) Object {
+	if err := checkArgCount("re.match", args, 2); err != nil {
+		return err
+	}
+	re, s, err := regexAndString("re.match", args)
+	if err != nil {
+		return err
+	}
+
+	loc := re.FindStringSubmatchIndex(s)
+	if loc == nil {
+		return NULL
+	}
+	return regexMatchValue(re, s, loc)
+}
+
+// builtinRegexFindAll finds successive non-overlapping matches, up to an
+// optional limit. Without capture groups each match is a string;
+// otherwise each is a match as returned by re.match.
+// Returns List.
+func builtinRegexFindAll(env *Environment, args ...Object) Object {
+	if len(args) != 2 && len(args) != 3 {
+		return newBuiltinError("wrong number of arguments to re.find_all: expected 2 or 3, got %d", len(args))
+	}
+	re, s, err := regexAndString("re.find_all", args)
+	if err != nil {
+		return err
+	}
+
+	limit := int64(-1)
+	if len(args) == 3 && args[2] != NULL {
+		limit, err = integerArgument("re.find_all", "limit", args[2])
+		if err != nil {
+			return err
+		}
+		if limit <= 0 {
+			return newBuiltinError("re.find_all limit must be positive, got %d", limit)
+		}
+	}
+
+	matches := re.FindAllStringSubmatchIndex(s, int(min(limit, math.MaxInt32)))
+	elements := make([]Object, len(matches))
+	for i, loc := range matches {
+		elements[i] = regexFoundValue(re, s, loc)
+	}
+	return &List{Elements: elements}
+}
+
+// builtinRegexReplace replaces matches of a pattern, all of them unless
+// count is given. A string replacement may refer to groups as $1 or
+// ${name}; a function replacement is called with each match, in the form
+// re.find_all gives it, and must return a string.
+// Returns String.
+func builtinRegexReplace(env *Environment, args ...Object) Object {
+	if len(args) != 3 && len(args) != 4 {
+		return newBuiltinError("wrong number of arguments to re.replace: expected 3 or 4, got %d", len(args))
+	}
+	re, s, err := regexAndString("re.replace", args)
+	if err != nil {
+		return err
+	}
+	replacement := args[2]
+	if replacement.Type() != STRING_OBJ && !isCallable(replacement) {
+		return newBuiltinError("re.replace replacement must be STRING or FUNCTION, got %s", replacement.Type())
+	}
+
+	count := int64(-1)
+	if len(args) == 4 && args[3] != NULL {
+		count, err = integerArgument("re.replace", "count", args[3])
+		if err != nil {
+			return err
+		}
+		if count < 0 {
+			return newBuiltinError("re.replace count must not be negative, got %d", count)
+		}
+	}
+
+	var out strings.Builder
+	last := 0
+	for _, loc := range re.FindAllStringSubmatchIndex(s, int(min(count, math.MaxInt32))) {
+		out.WriteString(s[last:loc[0]])
+		last = loc[1]
+
+		if template, ok := replacement.(*String); ok {
+			out.Write(re.ExpandString(nil, template.Value, s, loc))
+			continue
+		}
+
+		result := callFunction(env, replacement, regexFoundValue(re, s, loc))
+		if isError(result) {
+			return result
+		}
+		text, ok := result.(*String)
+		if !ok {
+			return newBuiltinError("re.replace callback must return STRING, got %s", result.Type())
+		}
+		out.WriteString(text.Value)
+	}
+	out.WriteString(s[last:])
+	return &String{Value: out.String()}
+}
+
+// builtinRegexSplit splits a string around matches of a pattern. The
+// optional limit caps the number of parts; the last part holds the
+// unsplit remainder.
+// Returns a List of Strings.
+func builtinRegexSplit(env *Environment, args ...Object) Object {
+	if len(args) != 2 && len(args) != 3 {
+		return newBuiltinError("wrong number of arguments to re.split: expected 2 or 3, got %d", len(args))
+	}
+	re, s, err := regexAndString("re.split", args)
+	if err != nil {
+		return err
+	}
+
+	limit := int64(-1)
+	if len(args) == 3 && args[2] != NULL {
+		limit, err = integerArgument("re.split", "limit", args[2])
+		if err != nil {
+			return err
+		}
+		if limit <= 0 {
+			return newBuiltinError("re.split limit must be positive, got %d", limit)
+		}
+	}
+	return stringList(re.Split(s, int(min(limit, math.MaxInt32))))
+}
+
+// regexMatchValue converts one match to a value. If the pattern has named
+// groups, the value is an object of the named groups; otherwise it is a
+// list of the whole match followed by each group. Groups that did not
+// take part in the match are NULL.
+func regexMatchValue(re *regexp.Regexp, s string, loc []int) Object {
+	group := func(i int) Object {
+		if loc[2*i] < 0 {
+			return NULL
+		}
+		return &String{Value: s[loc[2*i]:loc[2*i+1]]}
+	}
+
+	names := re.SubexpNames()
+	if hasNamedGroups(names) {
+		hash := NewHash()
+		for i, name := range names {
+			if name != "" {
+				hash.Set(name, group(i))
+			}
+		}
+		return hash
+	}
+
+	elements := make([]Object, len(names))
+	for i := range names {
+		elements[i] = group(i)
+	}
+	return &List{Elements: elements}
+}
+
+// regexFoundValue is the value of a match as re.find_all and re.replace
+// callbacks see it: the matched text if the pattern has no groups, and a
+// regexMatchValue otherwise.
+func regexFoundValue(re *regexp.Regexp, s string, loc []int) Object {
+	if re.NumSubexp() == 0 {
+		return &String{Value: s[loc[0]:loc[1]]}
+	}
+	return regexMatchValue(re, s, loc)
+}
+
+// hasNamedGroups reports whether any capture group has a name.
+func hasNamedGroups(names []string) bool {
+	for _, name := range names {
+		if name != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// regexAndString validates the (pattern, string) arguments shared by
+// the re functions.
+func regexAndString(name string, args []Object) (*regexp.Regexp, string, *Error) {
+	re, err := regexArgument(name, args[0])
+	if err != nil {
+		return nil, "", err
+	}
+	s, err := stringArgument(name, "string", args[1])
+	if err != nil {
+		return nil, "", err
+	}
+	return re, s, nil
+}
+
+// regexArgument returns the regex for a pattern argument, which is a
+// compiled Regex or a string to compile.
+func regexArgument(name string, arg Object) (*regexp.Regexp, *Error) {
+	switch arg := arg.(type) {
+	case *Regex:
+		return arg.Value, nil
+	case *String:
+		re, err := regexp.Compile(arg.Value)
+		if err != nil {
+			var syntaxErr *syntax.Error
+			if errors.As(err, &syntaxErr) {
+				return nil, newBuiltinError("invalid regex %q: %s", arg.Value, syntaxErr.Code)
+			}
+			return nil, newBuiltinError("invalid regex %q: %s", arg.Value, err)
+		}
+		return re, nil
+	default:
+		return nil, newBuiltinError("%s pattern must be STRING or REGEX, got %s", name, arg.Type())
+	}
+}
@@ -0,0 +1,84 @@
+package eval
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestBuiltinRegexFunctions(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`re.match("\d+", "order-1234-x");`, "[1234]"},
+		{`re.match("^\d+$", "order-1234");`, "null"},
+		{`re.match("USER#(\d+)#(\w+)", "pk=USER#42#PROFILE");`, "[USER#42#PROFILE, 42, PROFILE]"},
+		{`re.match("(a)|(b)", "b");`, "[b, null, b]"},
+		{`re.match("arn:aws:lambda:(?P<region>[a-z0-9-]+):(?P<account>\d{12}):function:(?P<name>.+)", "arn:aws:lambda:us-east-1:123456789012:function:orders");`, "{region: us-east-1, account: 123456789012, name: orders}"},
+		{`re.match("(?P<key>\w+)=(\d+)", "a=1");`, "{key: a}"},
+		{`re.match(pattern: "é+", string: "caféé");`, "[éé]"},
+		{`re.find_all("\d+", "a1 b22 c333");`, "[1, 22, 333]"},
+		{`re.find_all("\d+", "a1 b22 c333", 2);`, "[1, 22]"},
+		{`re.find_all("(\w)=(\d)", "a=1, b=2");`, "[[a=1, a, 1], [b=2, b, 2]]"},
+		{`re.find_all("(?P<k>\w)=(?P<v>\d)", "a=1 b=2");`, "[{k: a, v: 1}, {k: b, v: 2}]"},
+		{`re.find_all("x", "abc");`, "[]"},
+		{`re.replace("\s+", "a  b   c", " ");`, "a b c"},
+		{`re.replace("(\w+)@(\w+)", "jo@corp", "$2/$1");`, "corp/jo"},
+		{`re.replace("(?P<n>\d+)", "v1 v2", "<${n}>");`, "v<1> v<2>"},
+		{`re.replace("a", "aaaa", "b", 2);`, "bbaa"},
+		{`re.replace("a", "aaaa", "b", count: 0);`, "aaaa"},
+		{`fn up(m) { return upper(m); } re.replace("[a-z]+", "ab-cd", up);`, "AB-CD"},
+		{`fn swap(m) { return m[2] + m[1]; } re.replace("(\w)(\w)", "abcd", swap);`, "badc"},
+		{`re.split("\s*,\s*", "a , b,c ,d");`, "[a, b, c, d]"},
+		{`re.split("-", "a-b-c", 2);`, "[a, b-c]"},
+		{`re.split("x", "");`, "[]"},
+		{`r = re.compile("^fn-(\w+)$"); [re.match(r, "fn-api"), re.match(r, "api")];`, "[[fn-api, api], null]"},
+		{`type(re.compile("a"));`, "regex"},
+		{`re.compile("a+b");`, "regex:a+b"},
+		{`[n for n in ["api-prod", "api-dev", "worker-prod"] if n =~ "-prod$"];`, "[api-prod, worker-prod]"},
+		{`r = re.compile("^\d{12}$"); ["123456789012" =~ r, "1234" =~ r];`, "[true, false]"},
+		{`"Hello" =~ "(?i)^hello$";`, "true"},
+		{`!("abc" =~ "z");`, "true"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			var stdout bytes.Buffer
+			evaluated := testEvalWithBuiltins(tt.input, &stdout)
+			if evaluated.Inspect() != tt.expected {
+				t.Errorf("wrong result. got=%q, want=%q", evaluated.Inspect(), tt.expected)
+			}
+		})
+	}
+}
+
+func TestBuiltinRegexErrors(t *testing.T) {
+	tests := []struct {
+		input           string
+		expectedMessage string
+	}{
+		{`re.match("(", "x");`, `invalid regex "(": missing closing )`},
+		{`re.compile("a**");`, `invalid regex "a**": invalid nested repetition operator`},
+		{`re.compile(re.compile("a"));`, "argument to re.compile must be STRING, got REGEX"},
+		{`re.match(1, "x");`, "re.match pattern must be STRING or REGEX, got INTEGER"},
+		{`re.match("x", 1);`, "re.match string must be STRING, got INTEGER"},
+		{`re.match("x");`, "wrong number of arguments to re.match: expected 2, got 1"},
+		{`re.find_all("x", "x", 0);`, "re.find_all limit must be positive, got 0"},
+		{`re.split("x", "x", -1);`, "re.split limit must be positive, got -1"},
+		{`re.replace("x", "x", 1);`, "re.replace replacement must be STRING or FUNCTION, got INTEGER"},
+		{`re.replace("x", "x", "y", -1);`, "re.replace count must not be negative, got -1"},
+		{`fn bad(m) { return 1; } re.replace("x", "x", bad);`, "re.replace callback must return STRING, got INTEGER"},
+		{`re.search;`, "undefined member: re.search"},
+		{`1 =~ "x";`, "left side of =~ must be STRING, got INTEGER"},
+		{`"x" =~ 1;`, "right side of =~ must be STRING or REGEX, got INTEGER"},
+		{`"x" =~ "[";`, `invalid regex "[": missing closing ]`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			var stdout bytes.Buffer
+			evaluated := testEvalWithBuiltins(tt.input, &stdout)
+			testErrorObject(t, evaluated, tt.expectedMessage)
+		})
+	}
+}
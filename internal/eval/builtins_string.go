@@ -0,0 +1,457 @@
+package eval
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// builtinStartsWith reports whether a string begins with a prefix.
+// Returns Boolean.
+func builtinStartsWith(env *Environment, args ...Object) Object {
+	s, prefix, err := twoStrings("starts_with", "prefix", args)
+	if err != nil {
+		return err
+	}
+	return nativeBoolToBooleanObject(strings.HasPrefix(s, prefix))
+}
+
+// builtinEndsWith reports whether a string ends with a suffix.
+// Returns Boolean.
+func builtinEndsWith(env *Environment, args ...Object) Object {
+	s, suffix, err := twoStrings("ends_with", "suffix", args)
+	if err != nil {
+		return err
+	}
+	return nativeBoolToBooleanObject(strings.HasSuffix(s, suffix))
+}
+
+// builtinContains reports whether a string contains a substring.
+// Returns Boolean.
+func builtinContains(env *Environment, args ...Object) Object {
+	s, substring, err := twoStrings("contains", "substring", args)
+	if err != nil {
+		return err
+	}
+	return nativeBoolToBooleanObject(strings.Contains(s, substring))
+}
+
+// builtinIndexOf finds the first occurrence of a substring, counting in
+// characters rather than bytes.
+// Returns Integer, or -1 if the substring is not present.
+func builtinIndexOf(env *Environment, args ...Object) Object {
+	s, substring, err := twoStrings("index_of", "substring", args)
+	if err != nil {
+		return err
+	}
+
+	index := strings.Index(s, substring)
+	if index < 0 {
+		return &Integer{Value: -1}
+	}
+	return &Integer{Value: int64(utf8.RuneCountInString(s[:index]))}
+}
+
+// builtinSplit splits a string around each separator. Without a
+// separator it splits around runs of whitespace. The optional limit caps
+// the number of parts; the last part holds the unsplit remainder.
+// Returns a List of Strings.
+func builtinSplit(env *Environment, args ...Object) Object {
+	if len(args) < 1 || len(args) > 3 {
+		return newBuiltinError("wrong number of arguments to split: expected 1 to 3, got %d", len(args))
+	}
+	s, err := stringArgument("split", "", args[0])
+	if err != nil {
+		return err
+	}
+
+	limit := int64(-1)
+	if len(args) == 3 && args[2] != NULL {
+		limit, err = integerArgument("split", "limit", args[2])
+		if err != nil {
+			return err
+		}
+		if limit <= 0 {
+			return newBuiltinError("split limit must be positive, got %d", limit)
+		}
+	}
+
+	var parts []string
+	if len(args) == 1 || args[1] == NULL {
+		parts = splitFields(s, limit)
+	} else {
+		separator, err := stringArgument("split", "separator", args[1])
+		if err != nil {
+			return err
+		}
+		if separator == "" {
+			return newBuiltinError("split separator must not be empty")
+		}
+		parts = strings.SplitN(s, separator, int(min(limit, math.MaxInt32)))
+	}
+
+	return stringList(parts)
+}
+
+// splitFields splits s around runs of whitespace. If limit is positive,
+// at most limit parts are returned and the last holds the remainder of s
+// unchanged.
+func splitFields(s string, limit int64) []string {
+	parts := []string{}
+	rest := strings.TrimLeftFunc(s, unicode.IsSpace)
+	for rest != "" {
+		if limit > 0 && int64(len(parts)) == limit-1 {
+			return append(parts, rest)
+		}
+		end := strings.IndexFunc(rest, unicode.IsSpace)
+		if end < 0 {
+			return append(parts, rest)
+		}
+		parts = append(parts, rest[:end])
+		rest = strings.TrimLeftFunc(rest[end:], unicode.IsSpace)
+	}
+	return parts
+}
+
+// builtinJoin concatenates a list of strings with a separator between
+// each element. The separator defaults to the empty string.
+// Returns String.
+func builtinJoin(env *Environment, args ...Object) Object {
+	if len(args) != 1 && len(args) != 2 {
+		return newBuiltinError("wrong number of arguments to join: expected 1 or 2, got %d", len(args))
+	}
+	list, ok := args[0].(*List)
+	if !ok {
+		return newBuiltinError("argument to join must be LIST, got %s", args[0].Type())
+	}
+
+	separator := ""
+	if len(args) == 2 && args[1] != NULL {
+		var err *Error
+		separator, err = stringArgument("join", "separator", args[1])
+		if err != nil {
+			return err
+		}
+	}
+
+	parts := make([]string, len(list.Elements))
+	for i, elem := range list.Elements {
+		s, ok := elem.(*String)
+		if !ok {
+			return newBuiltinError("join requires a list of STRING, got %s at index %d", elem.Type(), i)
+		}
+		parts[i] = s.Value
+	}
+	return &String{Value: strings.Join(parts, separator)}
+}
+
+// builtinTrim removes leading and trailing whitespace, or any of the
+// given characters, from a string.
+// Returns String.
+func builtinTrim(env *Environment, args ...Object) Object {
+	if len(args) != 1 && len(args) != 2 {
+		return newBuiltinError("wrong number of arguments to trim: expected 1 or 2, got %d", len(args))
+	}
+	s, err := stringArgument("trim", "", args[0])
+	if err != nil {
+		return err
+	}
+
+	if len(args) == 1 || args[1] == NULL {
+		return &String{Value: strings.TrimSpace(s)}
+	}
+	chars, err := stringArgument("trim", "chars", args[1])
+	if err != nil {
+		return err
+	}
+	return &String{Value: strings.Trim(s, chars)}
+}
+
+// builtinReplace replaces occurrences of old with new in a string. The
+// optional count limits how many replacements are made, starting from
+// the left; by default every occurrence is replaced.
+// Returns String.
+func builtinReplace(env *Environment, args ...Object) Object {
+	if len(args) != 3 && len(args) != 4 {
+		return newBuiltinError("wrong number of arguments to replace: expected 3 or 4, got %d", len(args))
+	}
+	s, err := stringArgument("replace", "", args[0])
+	if err != nil {
+		return err
+	}
+	old, err := stringArgument("replace", "old", args[1])
+	if err != nil {
+		return err
+	}
+	replacement, err := stringArgument("replace", "new", args[2])
+	if err != nil {
+		return err
+	}
+
+	count := int64(-1)
+	if len(args) == 4 && args[3] != NULL {
+		count, err = integerArgument("replace", "count", args[3])
+		if err != nil {
+			return err
+		}
+		if count < 0 {
+			return newBuiltinError("replace count must not be negative, got %d", count)
+		}
+	}
+	return &String{Value: strings.Replace(s, old, replacement, int(min(count, math.MaxInt32)))}
+}
+
+// builtinUpper converts a string to upper case.
+// Returns String.
+func builtinUpper(env *Environment, args ...Object) Object {
+	if err := checkArgCount("upper", args, 1); err != nil {
+		return err
+	}
+	s, err := stringArgument("upper", "", args[0])
+	if err != nil {
+		return err
+	}
+	return &String{Value: strings.ToUpper(s)}
+}
+
+// builtinLower converts a string to lower case.
+// Returns String.
+func builtinLower(env *Environment, args ...Object) Object {
+	if err := checkArgCount("lower", args, 1); err != nil {
+		return err
+	}
+	s, err := stringArgument("lower", "", args[0])
+	if err != nil {
+		return err
+	}
+	return &String{Value: strings.ToLower(s)}
+}
+
+// builtinPadLeft pads a string on the left to a width in characters.
+// Returns String.
+func builtinPadLeft(env *Environment, args ...Object) Object {
+	return pad("pad_left", args, true)
+}
+
+// builtinPadRight pads a string on the right to a width in characters.
+// Returns String.
+func builtinPadRight(env *Environment, args ...Object) Object {
+	return pad("pad_right", args, false)
+}
+
+// pad implements pad_left and pad_right. The pad character defaults to a
+// space; strings already at least width characters long are unchanged.
+func pad(name string, args []Object, left bool) Object {
+	if len(args) != 2 && len(args) != 3 {
+		return newBuiltinError("wrong number of arguments to %s: expected 2 or 3, got %d", name, len(args))
+	}
+	s, err := stringArgument(name, "", args[0])
+	if err != nil {
+		return err
+	}
+	width, err := integerArgument(name, "width", args[1])
+	if err != nil {
+		return err
+	}
+
+	padding := " "
+	if len(args) == 3 && args[2] != NULL {
+		padding, err = stringArgument(name, "pad", args[2])
+		if err != nil {
+			return err
+		}
+		if utf8.RuneCountInString(padding) != 1 {
+			return newBuiltinError("%s pad must be a single character, got %q", name, padding)
+		}
+	}
+
+	missing := width - int64(utf8.RuneCountInString(s))
+	if missing <= 0 {
+		return &String{Value: s}
+	}
+	if missing > maxRepeatLength {
+		return newBuiltinError("%s width too large: %d", name, width)
+	}
+
+	fill := strings.Repeat(padding, int(missing))
+	if left {
+		return &String{Value: fill + s}
+	}
+	return &String{Value: s + fill}
+}
+
+// maxRepeatLength bounds the size of strings built by repeat and the pad
+// functions, so a bad count fails cleanly instead of exhausting memory.
+const maxRepeatLength = 1 << 30
+
+// builtinRepeat concatenates count copies of a string.
+// Returns String.
+func builtinRepeat(env *Environment, args ...Object) Object {
+	if err := checkArgCount("repeat", args, 2); err != nil {
+		return err
+	}
+	s, err := stringArgument("repeat", "", args[0])
+	if err != nil {
+		return err
+	}
+	count, err := integerArgument("repeat", "count", args[1])
+	if err != nil {
+		return err
+	}
+	if count < 0 {
+		return newBuiltinError("repeat count must not be negative, got %d", count)
+	}
+	if len(s) > 0 && count > maxRepeatLength/int64(len(s)) {
+		return newBuiltinError("repeat result too large: %d copies of %d bytes", count, len(s))
+	}
+	return &String{Value: strings.Repeat(s, int(count))}
+}
+
+// builtinSprintf formats values according to a printf-style format. Each
+// verb takes the next argument: %d, %b and %o an integer, %x and %X an
+// integer or string, %f, %e and %g a number, %t a boolean, and %s, %q and
+// %v any value in its printed form. %% is a literal percent sign. Flags,
+// width and precision work as in Go.
+// Returns String.
+func builtinSprintf(env *Environment, args ...Object) Object {
+	if len(args) == 0 {
+		return newBuiltinError("wrong number of arguments to sprintf: expected at least 1, got 0")
+	}
+	format, err := stringArgument("sprintf", "format", args[0])
+	if err != nil {
+		return err
+	}
+	values := args[1:]
+
+	var out strings.Builder
+	next := 0
+	for i := 0; i < len(format); i++ {
+		if format[i] != '%' {
+			out.WriteByte(format[i])
+			continue
+		}
+
+		end := i + 1
+		for end < len(format) && strings.IndexByte("+-# 0123456789.", format[end]) >= 0 {
+			end++
+		}
+		if end == len(format) {
+			return newBuiltinError("sprintf: incomplete verb %q at end of format", format[i:])
+		}
+		spec, verb := format[i:end+1], format[end]
+		i = end
+
+		if verb == '%' {
+			out.WriteByte('%')
+			continue
+		}
+		if next == len(values) {
+			return newBuiltinError("sprintf: missing argument for %s", spec)
+		}
+		value, err := sprintfValue(spec, verb, values[next])
+		if err != nil {
+			return err
+		}
+		next++
+		fmt.Fprintf(&out, spec, value)
+	}
+
+	if next < len(values) {
+		return newBuiltinError("sprintf: too many arguments: format uses %d, got %d", next, len(values))
+	}
+	return &String{Value: out.String()}
+}
+
+// sprintfValue converts the argument for one sprintf verb to the Go value
+// fmt expects, checking that its type suits the verb.
+func sprintfValue(spec string, verb byte, arg Object) (any, *Error) {
+	switch verb {
+	case 'd', 'b', 'o':
+		if i, ok := arg.(*Integer); ok {
+			return i.Value, nil
+		}
+		return nil, newBuiltinError("sprintf: %s requires INTEGER, got %s", spec, arg.Type())
+	case 'x', 'X':
+		switch arg := arg.(type) {
+		case *Integer:
+			return arg.Value, nil
+		case *String:
+			return arg.Value, nil
+		}
+		return nil, newBuiltinError("sprintf: %s requires INTEGER or STRING, got %s", spec, arg.Type())
+	case 'e', 'E', 'f', 'F', 'g', 'G':
+		switch arg := arg.(type) {
+		case *Integer:
+			return float64(arg.Value), nil
+		case *Float:
+			return arg.Value, nil
+		}
+		return nil, newBuiltinError("sprintf: %s requires INTEGER or FLOAT, got %s", spec, arg.Type())
+	case 't':
+		if b, ok := arg.(*Boolean); ok {
+			return b.Value, nil
+		}
+		return nil, newBuiltinError("sprintf: %s requires BOOLEAN, got %s", spec, arg.Type())
+	case 's', 'q', 'v':
+		return arg.Inspect(), nil
+	default:
+		return nil, newBuiltinError("sprintf: unknown verb %s", spec)
+	}
+}
+
+// twoStrings validates the (string, other) arguments shared by the
+// predicate-style string builtins.
+func twoStrings(name, param string, args []Object) (string, string, *Error) {
+	if err := checkArgCount(name, args, 2); err != nil {
+		return "", "", err
+	}
+	s, err := stringArgument(name, "", args[0])
+	if err != nil {
+		return "", "", err
+	}
+	other, err := stringArgument(name, param, args[1])
+	if err != nil {
+		return "", "", err
+	}
+	return s, other, nil
+}
+
+// stringArgument unwraps a String argument. param names the parameter in
+// the error message; an empty param refers to the builtin's main argument.
+func stringArgument(name, param string, arg Object) (string, *Error) {
+	s, ok := arg.(*String)
+	if !ok {
+		return "", argumentTypeError(name, param, STRING_OBJ, arg)
+	}
+	return s.Value, nil
+}
+
+// integerArgument unwraps an Integer argument. param names the parameter
+// in the error message; an empty param refers to the builtin's main
+// argument.
+func integerArgument(name, param string, arg Object) (int64, *Error) {
+	i, ok := arg.(*Integer)
+	if !ok {
+		return 0, argumentTypeError(name, param, INTEGER_OBJ, arg)
+	}
+	return i.Value, nil
+}
+
+// argumentTypeError reports an argument of the wrong type.
+func argumentTypeError(name, param string, expected ObjectType, arg Object) *Error {
+	if param == "" {
+		return newBuiltinError("argument to %s must be %s, got %s", name, expected, arg.Type())
+	}
+	return newBuiltinError("%s %s must be %s, got %s", name, param, expected, arg.Type())
+}
+
+// stringList converts Go strings to a List of Strings.
+func stringList(values []string) *List {
+	elements := make([]Object, len(values))
+	for i, value := range values {
+		elements[i] = &String{Value: value}
+	}
+	return &List{Elements: elements}
+}
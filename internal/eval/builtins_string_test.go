@@ -0,0 +1,106 @@
+package eval
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestBuiltinStringFunctions(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`starts_with("USER#123", "USER#");`, "true"},
+		{`starts_with("ORG#1", "USER#");`, "false"},
+		{`ends_with("report.csv", ".csv");`, "true"},
+		{`ends_with("report.csv", ".json");`, "false"},
+		{`contains("us-west-2", "west");`, "true"},
+		{`contains("us-west-2", "east");`, "false"},
+		{`index_of("USER#123", "#");`, "4"},
+		{`index_of("héllo", "l");`, "2"},
+		{`index_of("abc", "z");`, "-1"},
+		{`split("USER#123#2024", "#");`, "[USER, 123, 2024]"},
+		{`split("a,b,c", ",", 2);`, "[a, b,c]"},
+		{`split("a,b,c", ",", limit: 1);`, "[a,b,c]"},
+		{"split(\"  a  b\tc \");", "[a, b, c]"},
+		{`split("  a  b  c ", limit: 2);`, "[a, b  c ]"},
+		{`split("", ",");`, "[]"},
+		{`len(split("", ","));`, "1"},
+		{`join(["a", "b", "c"], "#");`, "a#b#c"},
+		{`join(["a", "b"]);`, "ab"},
+		{`join([], ",");`, ""},
+		{"trim(\"  hi \n\");", "hi"},
+		{`trim("--hi--", "-");`, "hi"},
+		{`replace("a-b-c", "-", "_");`, "a_b_c"},
+		{`replace("a-b-c", "-", "_", 1);`, "a_b-c"},
+		{`replace("a-b-c", "-", "_", count: 0);`, "a-b-c"},
+		{`upper("Hello");`, "HELLO"},
+		{`lower("Hello");`, "hello"},
+		{`pad_left("7", 3, "0");`, "007"},
+		{`pad_left("é", 3);`, "  é"},
+		{`pad_right("ab", 4, ".");`, "ab.."},
+		{`pad_right("abcdef", 3);`, "abcdef"},
+		{`repeat("ab", 3);`, "ababab"},
+		{`repeat("ab", 0);`, ""},
+		{`len("日本語");`, "3"},
+		{`sprintf("%s has %d items", "Users", 42);`, "Users has 42 items"},
+		{`sprintf("%.2f%%", 12.3456);`, "12.35%"},
+		{`sprintf("%8.1f|%-6s|%05d", 3, "ab", 42);`, "     3.0|ab    |00042"},
+		{`sprintf("%x %X %o %b", 255, "hi", 8, 5);`, "ff 6869 10 101"},
+		{`sprintf("%e %g", 1500000, 0.5);`, "1.500000e+06 0.5"},
+		{`sprintf("%v %s %t", [1, 2.5], null, true);`, "[1, 2.5] null true"},
+		{`sprintf("%q", "hi");`, `"hi"`},
+		{`sprintf("no verbs");`, "no verbs"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			var stdout bytes.Buffer
+			evaluated := testEvalWithBuiltins(tt.input, &stdout)
+			if evaluated.Inspect() != tt.expected {
+				t.Errorf("wrong result. got=%q, want=%q", evaluated.Inspect(), tt.expected)
+			}
+		})
+	}
+}
+
+func TestBuiltinStringErrors(t *testing.T) {
+	tests := []struct {
+		input           string
+		expectedMessage string
+	}{
+		{`starts_with("a");`, "wrong number of arguments to starts_with: expected 2, got 1"},
+		{`starts_with(1, "a");`, "argument to starts_with must be STRING, got INTEGER"},
+		{`ends_with("a", 1);`, "ends_with suffix must be STRING, got INTEGER"},
+		{`split("a", "");`, "split separator must not be empty"},
+		{`split("a", ",", 0);`, "split limit must be positive, got 0"},
+		{`split("a", ",", "2");`, "split limit must be INTEGER, got STRING"},
+		{`join("abc", ",");`, "argument to join must be LIST, got STRING"},
+		{`join(["a", 1], ",");`, "join requires a list of STRING, got INTEGER at index 1"},
+		{`trim(5);`, "argument to trim must be STRING, got INTEGER"},
+		{`replace("a", "b");`, "wrong number of arguments to replace: expected 3 or 4, got 2"},
+		{`replace("a", "b", "c", -1);`, "replace count must not be negative, got -1"},
+		{`upper(null);`, "argument to upper must be STRING, got NULL"},
+		{`pad_left("a", "3");`, "pad_left width must be INTEGER, got STRING"},
+		{`pad_right("a", 3, "ab");`, `pad_right pad must be a single character, got "ab"`},
+		{`repeat("a", -1);`, "repeat count must not be negative, got -1"},
+		{`repeat("ab", 9223372036854775807);`, "repeat result too large: 9223372036854775807 copies of 2 bytes"},
+		{`sprintf();`, "wrong number of arguments to sprintf: expected at least 1, got 0"},
+		{`sprintf(1);`, "sprintf format must be STRING, got INTEGER"},
+		{`sprintf("%d", 1.5);`, "sprintf: %d requires INTEGER, got FLOAT"},
+		{`sprintf("%.1f", "1.5");`, "sprintf: %.1f requires INTEGER or FLOAT, got STRING"},
+		{`sprintf("%t", 1);`, "sprintf: %t requires BOOLEAN, got INTEGER"},
+		{`sprintf("%y", 1);`, "sprintf: unknown verb %y"},
+		{`sprintf("%s and %s", "a");`, "sprintf: missing argument for %s"},
+		{`sprintf("%s", "a", "b");`, "sprintf: too many arguments: format uses 1, got 2"},
+		{`sprintf("100%");`, `sprintf: incomplete verb "%" at end of format`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			var stdout bytes.Buffer
+			evaluated := testEvalWithBuiltins(tt.input, &stdout)
+			testErrorObject(t, evaluated, tt.expectedMessage)
+		})
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"io"
 	"os"
 	"testing"
+	"time"
 
 	"github.com/boattime/awsl/internal/lexer"
 	"github.com/boattime/awsl/internal/parser"
@@ -64,6 +65,220 @@ func TestBuiltinClock(t *testing.T) {
 	}
 }
 
+func TestBuiltinNow(t *testing.T) {
+	var stdout bytes.Buffer
+	before := time.Now()
+	obj := testEvalWithBuiltins(`now();`, &stdout)
+	result, ok := obj.(*Time)
+	if !ok {
+		t.Fatalf("object is not Time. got=%T (%+v)", obj, obj)
+	}
+	if result.Value.Before(before.Add(-time.Second)) || result.Value.After(time.Now().Add(time.Second)) {
+		t.Errorf("now() returned %s, expected roughly %s", result.Value, before)
+	}
+}
+
+func TestBuiltinSleep(t *testing.T) {
+	var stdout bytes.Buffer
+	obj := testEvalWithBuiltins(`sleep(1ms);`, &stdout)
+	testNullObject(t, obj)
+}
+
+func TestBuiltinExit(t *testing.T) {
+	tests := []struct {
+		input        string
+		expectedCode int
+	}{
+		{`exit();`, 0},
+		{`exit(null);`, 0},
+		{`exit(3);`, 3},
+		{`for (x in [1, 2, 3]) { if (x == 2) { exit(x); } }`, 2},
+		{`fn stop() { exit(5); return 1; } stop() + 1;`, 5},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			var stdout bytes.Buffer
+			evaluated := testEvalWithBuiltins(tt.input, &stdout)
+			errObj, ok := evaluated.(*Error)
+			if !ok {
+				t.Fatalf("object is not Error. got=%T (%+v)", evaluated, evaluated)
+			}
+			if errObj.Category != ExitRequest {
+				t.Errorf("wrong category. got=%d, want=%d", errObj.Category, ExitRequest)
+			}
+			if errObj.ExitCode != tt.expectedCode {
+				t.Errorf("wrong exit code. got=%d, want=%d", errObj.ExitCode, tt.expectedCode)
+			}
+		})
+	}
+}
+
+func TestBuiltinExitStopsEvaluation(t *testing.T) {
+	var stdout bytes.Buffer
+	testEvalWithBuiltins(`print("before"); exit(1); print("after");`, &stdout)
+	testStdout(t, stdout, "before\n")
+}
+
+func TestBuiltinTimeErrors(t *testing.T) {
+	tests := []struct {
+		input           string
+		expectedMessage string
+	}{
+		{`now(1);`, "wrong number of arguments to now: expected 0, got 1"},
+		{`exit(1, 2);`, "wrong number of arguments to exit: expected 0 or 1, got 2"},
+		{`exit("1");`, "argument to exit must be INTEGER, got STRING"},
+		{`exit(-1);`, "exit code must be between 0 and 255, got -1"},
+		{`sleep();`, "wrong number of arguments to sleep: expected 1, got 0"},
+		{`sleep(5);`, "argument to sleep must be DURATION, got INTEGER"},
+		{`sleep(-1s);`, "sleep duration must not be negative, got -1s"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			var stdout bytes.Buffer
+			evaluated := testEvalWithBuiltins(tt.input, &stdout)
+			testErrorObject(t, evaluated, tt.expectedMessage)
+		})
+	}
+}
+
+func TestBuiltinLen(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected int64
+	}{
+		{`len("");`, 0},
+		{`len("hello");`, 5},
+		{`len("héllo");`, 5},
+		{`len([]);`, 0},
+		{`len([1, 2, 3]);`, 3},
+		{`len({});`, 0},
+		{`len({a: 1, b: 2});`, 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			var stdout bytes.Buffer
+			testIntegerObject(t, testEvalWithBuiltins(tt.input, &stdout), tt.expected)
+		})
+	}
+}
+
+func TestBuiltinType(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`type(42);`, "int"},
+		{`type(1.5);`, "float"},
+		{`type("s");`, "string"},
+		{`type(true);`, "bool"},
+		{`type(null);`, "null"},
+		{`type([1]);`, "list"},
+		{`type({a: 1});`, "object"},
+		{`type(5s);`, "duration"},
+		{`type(now());`, "time"},
+		{`fn f() { return 1; } type(f);`, "function"},
+		{`type(print);`, "function"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			var stdout bytes.Buffer
+			testStringObject(t, testEvalWithBuiltins(tt.input, &stdout), tt.expected)
+		})
+	}
+}
+
+func TestBuiltinConversions(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected any
+	}{
+		{`str(42);`, "42"},
+		{`str(1.5);`, "1.5"},
+		{`str("s");`, "s"},
+		{`str(true);`, "true"},
+		{`str(null);`, "null"},
+		{`str([1, 2]);`, "[1, 2]"},
+		{`str(90s);`, "1m30s"},
+		{`int(42);`, int64(42)},
+		{`int(3.9);`, int64(3)},
+		{`int(-3.9);`, int64(-3)},
+		{`int("123");`, int64(123)},
+		{`int(" -7 ");`, int64(-7)},
+		{`int(true);`, int64(1)},
+		{`int(false);`, int64(0)},
+		{`float(2);`, 2.0},
+		{`float(2.5);`, 2.5},
+		{`float("1e3");`, 1000.0},
+		{`bool(0);`, true},
+		{`bool("");`, true},
+		{`bool(false);`, false},
+		{`bool(null);`, false},
+		{`bool([1]);`, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			var stdout bytes.Buffer
+			evaluated := testEvalWithBuiltins(tt.input, &stdout)
+			switch expected := tt.expected.(type) {
+			case string:
+				testStringObject(t, evaluated, expected)
+			case int64:
+				testIntegerObject(t, evaluated, expected)
+			case float64:
+				testFloatObject(t, evaluated, expected)
+			case bool:
+				testBooleanObject(t, evaluated, expected)
+			}
+		})
+	}
+}
+
+func TestBuiltinCoreErrors(t *testing.T) {
+	tests := []struct {
+		input           string
+		expectedMessage string
+	}{
+		{`len();`, "wrong number of arguments to len: expected 1, got 0"},
+		{`len("a", "b");`, "wrong number of arguments to len: expected 1, got 2"},
+		{`len(5);`, "argument to len not supported, got INTEGER"},
+		{`type();`, "wrong number of arguments to type: expected 1, got 0"},
+		{`str(1, 2);`, "wrong number of arguments to str: expected 1, got 2"},
+		{`int("abc");`, `cannot convert "abc" to int`},
+		{`int("1.5");`, `cannot convert "1.5" to int`},
+		{`int("99999999999999999999");`, `cannot convert "99999999999999999999" to int`},
+		{`int(1e30);`, "cannot convert 1e+30 to int: out of range"},
+		{`int([1]);`, "cannot convert LIST to int"},
+		{`float("x");`, `cannot convert "x" to float`},
+		{`float(true);`, "cannot convert BOOLEAN to float"},
+		{`bool();`, "wrong number of arguments to bool: expected 1, got 0"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			var stdout bytes.Buffer
+			evaluated := testEvalWithBuiltins(tt.input, &stdout)
+			testErrorObject(t, evaluated, tt.expectedMessage)
+		})
+	}
+}
+
+func TestBuiltinErrorPosition(t *testing.T) {
+	var stdout bytes.Buffer
+	evaluated := testEvalWithBuiltins("x = 1;\n  sleep(5);", &stdout)
+	errObj, ok := evaluated.(*Error)
+	if !ok {
+		t.Fatalf("object is not Error. got=%T (%+v)", evaluated, evaluated)
+	}
+	if errObj.Line != 2 || errObj.Column != 3 {
+		t.Errorf("wrong error position. got=%d:%d, want=2:3", errObj.Line, errObj.Column)
+	}
+}
+
 func TestRegisterBuiltins(t *testing.T) {
 	env := NewEnvironment(os.Stdout)
 	RegisterBuiltins(env)
@@ -73,6 +288,79 @@ func TestRegisterBuiltins(t *testing.T) {
 	}{
 		{"print", "print"},
 		{"clock", "clock"},
+		{"now", "now"},
+		{"parse_time", "parse_time"},
+		{"format_time", "format_time"},
+		{"in_zone", "in_zone"},
+		{"from_unix", "from_unix"},
+		{"env", "env"},
+		{"exit", "exit"},
+		{"sleep", "sleep"},
+		{"len", "len"},
+		{"type", "type"},
+		{"str", "str"},
+		{"int", "int"},
+		{"float", "float"},
+		{"bool", "bool"},
+		{"map", "map"},
+		{"filter", "filter"},
+		{"reduce", "reduce"},
+		{"sort", "sort"},
+		{"sort_by", "sort_by"},
+		{"group_by", "group_by"},
+		{"unique", "unique"},
+		{"flatten", "flatten"},
+		{"zip", "zip"},
+		{"chunk", "chunk"},
+		{"sum", "sum"},
+		{"min", "min"},
+		{"max", "max"},
+		{"abs", "abs"},
+		{"floor", "floor"},
+		{"ceil", "ceil"},
+		{"round", "round"},
+		{"sqrt", "sqrt"},
+		{"pow", "pow"},
+		{"clamp", "clamp"},
+		{"mean", "mean"},
+		{"median", "median"},
+		{"percentile", "percentile"},
+		{"any", "any"},
+		{"all", "all"},
+		{"starts_with", "starts_with"},
+		{"ends_with", "ends_with"},
+		{"contains", "contains"},
+		{"index_of", "index_of"},
+		{"split", "split"},
+		{"join", "join"},
+		{"trim", "trim"},
+		{"replace", "replace"},
+		{"upper", "upper"},
+		{"lower", "lower"},
+		{"pad_left", "pad_left"},
+		{"pad_right", "pad_right"},
+		{"repeat", "repeat"},
+		{"sprintf", "sprintf"},
+		{"format_number", "format_number"},
+		{"keys", "keys"},
+		{"values", "values"},
+		{"entries", "entries"},
+		{"has", "has"},
+		{"merge", "merge"},
+		{"deep_merge", "deep_merge"},
+		{"pick", "pick"},
+		{"omit", "omit"},
+		{"get_path", "get_path"},
+		{"set_path", "set_path"},
+		{"diff", "diff"},
+		{"read_file", "read_file"},
+		{"read_lines", "read_lines"},
+		{"write_file", "write_file"},
+		{"append_file", "append_file"},
+		{"glob", "glob"},
+		{"exists", "exists"},
+		{"mkdir", "mkdir"},
+		{"remove", "remove"},
 	}
 
 	for _, tt := range tests {
@@ -95,6 +383,46 @@ func TestRegisterBuiltins(t *testing.T) {
 	}
 }
 
+func TestRegisterNamespaces(t *testing.T) {
+	env := NewEnvironment(os.Stdout)
+	RegisterBuiltins(env)
+	tests := []struct {
+		namespace string
+		members   []string
+	}{
+		{"json", []string{"parse", "stringify"}},
+		{"yaml", []string{"parse", "stringify"}},
+		{"toml", []string{"parse"}},
+		{"csv", []string{"parse"}},
+		{"re", []string{"compile", "match", "find_all", "replace", "split"}},
+		{"log", []string{"debug", "info", "warn", "error"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.namespace, func(t *testing.T) {
+			val, ok := env.Get(tt.namespace)
+			if !ok {
+				t.Fatalf("expected '%s' to be registered", tt.namespace)
+			}
+
+			namespace, ok := val.(*Namespace)
+			if !ok {
+				t.Fatalf("expected *Namespace, got %T", val)
+			}
+
+			for _, member := range tt.members {
+				builtin, ok := namespace.Members[member].(*Builtin)
+				if !ok {
+					t.Fatalf("expected member %s to be *Builtin, got %T", member, namespace.Members[member])
+				}
+				if want := tt.namespace + "." + member; builtin.Name != want {
+					t.Errorf("expected name %q, got %q", want, builtin.Name)
+				}
+			}
+		})
+	}
+}
+
 // testStdout checks that bytes is the expected value.
 func testStdout(t *testing.T, stdout bytes.Buffer, expected string) bool {
 	t.Helper()
@@ -0,0 +1,210 @@
+package eval
+
+import (
+	"math"
+	"strings"
+	"time"
+	_ "time/tzdata" // time zones work without a system zoneinfo database
+)
+
+// namedTimeLayouts are the layout names accepted by parse_time and
+// format_time in place of a Go reference layout.
+var namedTimeLayouts = map[string]string{
+	"rfc3339":     time.RFC3339,
+	"rfc3339nano": time.RFC3339Nano,
+	"iso8601":     time.RFC3339,
+	"rfc1123":     time.RFC1123,
+	"date":        time.DateOnly,
+	"datetime":    time.DateTime,
+	"time":        time.TimeOnly,
+}
+
+// isoTimeLayouts are tried in order by parse_time without a layout.
+// Together they cover RFC 3339, the ISO 8601 forms AWS APIs return (such
+// as Lambda's 2024-01-02T03:04:05.000+0000) and plain dates. A
+// fractional second is accepted after the seconds by every layout.
+var isoTimeLayouts = []string{
+	time.RFC3339,
+	"2006-01-02T15:04:05Z0700",
+	"2006-01-02T15:04:05",
+	time.DateTime,
+	time.DateOnly,
+}
+
+// builtinParseTime parses a string as a time. Without a layout, ISO 8601
+// forms are accepted. layout is a name such as "date" or a Go reference
+// layout such as "02/01/2006 15:04". Times without an offset are taken
+// to be in zone, UTC by default.
+// Returns Time.
+func builtinParseTime(env *Environment, args ...Object) Object {
+	if len(args) < 1 || len(args) > 3 {
+		return newBuiltinError("wrong number of arguments to parse_time: expected 1 to 3, got %d", len(args))
+	}
+	s, err := stringArgument("parse_time", "", args[0])
+	if err != nil {
+		return err
+	}
+
+	loc := time.UTC
+	if len(args) == 3 && args[2] != NULL {
+		loc, err = zoneArgument("parse_time", args[2])
+		if err != nil {
+			return err
+		}
+	}
+
+	if len(args) == 1 || args[1] == NULL {
+		for _, layout := range isoTimeLayouts {
+			if t, parseErr := time.ParseInLocation(layout, s, loc); parseErr == nil {
+				return &Time{Value: t}
+			}
+		}
+		return newBuiltinError("parse_time: cannot parse %q as an ISO 8601 time", s)
+	}
+
+	layout, err := layoutArgument("parse_time", args[1])
+	if err != nil {
+		return err
+	}
+	t, parseErr := time.ParseInLocation(layout, s, loc)
+	if parseErr != nil {
+		return newBuiltinError("parse_time: cannot parse %q with layout %q", s, layout)
+	}
+	return &Time{Value: t}
+}
+
+// builtinFormatTime formats a time. Without a layout, it uses RFC 3339
+// with as many fractional second digits as needed.
+// Returns String.
+func builtinFormatTime(env *Environment, args ...Object) Object {
+	if len(args) != 1 && len(args) != 2 {
+		return newBuiltinError("wrong number of arguments to format_time: expected 1 or 2, got %d", len(args))
+	}
+	t, ok := args[0].(*Time)
+	if !ok {
+		return argumentTypeError("format_time", "", TIME_OBJ, args[0])
+	}
+
+	layout := time.RFC3339Nano
+	if len(args) == 2 && args[1] != NULL {
+		var err *Error
+		layout, err = layoutArgument("format_time", args[1])
+		if err != nil {
+			return err
+		}
+	}
+	return &String{Value: t.Value.Format(layout)}
+}
+
+// builtinInZone converts a time to another time zone. The instant is
+// unchanged; only its components and printed offset differ.
+// Returns Time.
+func builtinInZone(env *Environment, args ...Object) Object {
+	if err := checkArgCount("in_zone", args, 2); err != nil {
+		return err
+	}
+	t, ok := args[0].(*Time)
+	if !ok {
+		return argumentTypeError("in_zone", "", TIME_OBJ, args[0])
+	}
+	loc, err := zoneArgument("in_zone", args[1])
+	if err != nil {
+		return err
+	}
+	return &Time{Value: t.Value.In(loc)}
+}
+
+// builtinFromUnix converts seconds since the Unix epoch, as used by
+// DynamoDB TTL attributes, to a time. Floats keep fractional seconds.
+// Returns Time in UTC.
+func builtinFromUnix(env *Environment, args ...Object) Object {
+	if err := checkArgCount("from_unix", args, 1); err != nil {
+		return err
+	}
+
+	switch seconds := args[0].(type) {
+	case *Integer:
+		return &Time{Value: time.Unix(seconds.Value, 0).UTC()}
+	case *Float:
+		if math.IsNaN(seconds.Value) || math.Abs(seconds.Value) >= math.MaxInt64 {
+			return newBuiltinError("from_unix: %s is out of range", seconds.Inspect())
+		}
+		whole, frac := math.Modf(seconds.Value)
+		return &Time{Value: time.Unix(int64(whole), int64(math.Round(frac*1e9))).UTC()}
+	default:
+		return newBuiltinError("argument to from_unix must be INTEGER or FLOAT, got %s", args[0].Type())
+	}
+}
+
+// timeField returns a component of a time, for member access such as
+// t.year. Components are in the time's own zone.
+func timeField(t *Time, name string) (Object, bool) {
+	v := t.Value
+	switch name {
+	case "year":
+		return &Integer{Value: int64(v.Year())}, true
+	case "month":
+		return &Integer{Value: int64(v.Month())}, true
+	case "day":
+		return &Integer{Value: int64(v.Day())}, true
+	case "hour":
+		return &Integer{Value: int64(v.Hour())}, true
+	case "minute":
+		return &Integer{Value: int64(v.Minute())}, true
+	case "second":
+		return &Integer{Value: int64(v.Second())}, true
+	case "millisecond":
+		return &Integer{Value: int64(v.Nanosecond() / int(time.Millisecond))}, true
+	case "nanosecond":
+		return &Integer{Value: int64(v.Nanosecond())}, true
+	case "weekday":
+		return &String{Value: v.Weekday().String()}, true
+	case "yday":
+		return &Integer{Value: int64(v.YearDay())}, true
+	case "unix":
+		return &Integer{Value: v.Unix()}, true
+	case "unix_ms":
+		return &Integer{Value: v.UnixMilli()}, true
+	case "zone":
+		name, _ := v.Zone()
+		return &String{Value: name}, true
+	case "offset":
+		_, offset := v.Zone()
+		return &Duration{Value: time.Duration(offset) * time.Second}, true
+	default:
+		return nil, false
+	}
+}
+
+// layoutArgument returns the Go layout for a layout argument, which is
+// either a name from namedTimeLayouts or a Go reference layout.
+func layoutArgument(name string, arg Object) (string, *Error) {
+	layout, err := stringArgument(name, "layout", arg)
+	if err != nil {
+		return "", err
+	}
+	if named, ok := namedTimeLayouts[strings.ToLower(layout)]; ok {
+		return named, nil
+	}
+	if layout == "" {
+		return "", newBuiltinError("%s layout must not be empty", name)
+	}
+	return layout, nil
+}
+
+// zoneArgument loads the time zone named by an IANA name such as
+// "America/New_York", or "UTC" or "Local".
+func zoneArgument(name string, arg Object) (*time.Location, *Error) {
+	zone, err := stringArgument(name, "zone", arg)
+	if err != nil {
+		return nil, err
+	}
+	if zone == "" {
+		return nil, newBuiltinError("%s zone must not be empty", name)
+	}
+	loc, loadErr := time.LoadLocation(zone)
+	if loadErr != nil {
+		return nil, newBuiltinError("%s: unknown time zone %q", name, zone)
+	}
+	return loc, nil
+}
@@ -0,0 +1,193 @@
+package eval
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestBuiltinParseTime(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`format_time(parse_time("2024-03-15T09:30:00Z"));`, "2024-03-15T09:30:00Z"},
+		{`format_time(parse_time("2024-03-15T09:30:00.25+02:00"));`, "2024-03-15T09:30:00.25+02:00"},
+		{`format_time(parse_time("2024-03-15T09:30:00.000+0000"));`, "2024-03-15T09:30:00Z"},
+		{`format_time(parse_time("2024-03-15T09:30:00"));`, "2024-03-15T09:30:00Z"},
+		{`format_time(parse_time("2024-03-15 09:30:00"));`, "2024-03-15T09:30:00Z"},
+		{`format_time(parse_time("2024-03-15"));`, "2024-03-15T00:00:00Z"},
+		{`format_time(parse_time("15/03/2024 09:30", "02/01/2006 15:04"));`, "2024-03-15T09:30:00Z"},
+		{`format_time(parse_time("2024-03-15", "date"));`, "2024-03-15T00:00:00Z"},
+		{`format_time(parse_time("2024-03-15", "DATE"));`, "2024-03-15T00:00:00Z"},
+		{`format_time(parse_time("2024-03-15 09:30:00", zone: "America/New_York"));`, "2024-03-15T09:30:00-04:00"},
+		{`format_time(parse_time("2024-03-15T09:30:00Z", zone: "Asia/Tokyo"));`, "2024-03-15T09:30:00Z"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			var stdout bytes.Buffer
+			evaluated := testEvalWithBuiltins(tt.input, &stdout)
+			testStringObject(t, evaluated, tt.expected)
+		})
+	}
+}
+
+func TestBuiltinFormatTime(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`format_time(parse_time("2024-03-15T09:30:00.123456789Z"));`, "2024-03-15T09:30:00.123456789Z"},
+		{`format_time(parse_time("2024-03-15T09:30:00.5Z"), "rfc3339");`, "2024-03-15T09:30:00Z"},
+		{`format_time(parse_time("2024-03-15T09:30:00Z"), "datetime");`, "2024-03-15 09:30:00"},
+		{`format_time(parse_time("2024-03-15T09:30:00Z"), "time");`, "09:30:00"},
+		{`format_time(parse_time("2024-03-15T09:30:00Z"), "rfc1123");`, "Fri, 15 Mar 2024 09:30:00 UTC"},
+		{`format_time(parse_time("2024-03-15T09:30:00Z"), "Jan 2, 2006");`, "Mar 15, 2024"},
+		{`str(parse_time("2024-03-15T09:30:00.5Z"));`, "2024-03-15T09:30:00Z"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			var stdout bytes.Buffer
+			evaluated := testEvalWithBuiltins(tt.input, &stdout)
+			testStringObject(t, evaluated, tt.expected)
+		})
+	}
+}
+
+func TestBuiltinInZone(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`format_time(in_zone(parse_time("2024-01-15T12:00:00Z"), "America/New_York"));`, "2024-01-15T07:00:00-05:00"},
+		{`format_time(in_zone(parse_time("2024-07-15T12:00:00Z"), "America/New_York"));`, "2024-07-15T08:00:00-04:00"},
+		{`format_time(in_zone(parse_time("2024-01-15T12:00:00+09:00"), "UTC"));`, "2024-01-15T03:00:00Z"},
+		{`t = parse_time("2024-01-15T12:00:00Z"); str(in_zone(t, "Asia/Tokyo") == t);`, "true"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			var stdout bytes.Buffer
+			evaluated := testEvalWithBuiltins(tt.input, &stdout)
+			testStringObject(t, evaluated, tt.expected)
+		})
+	}
+}
+
+func TestBuiltinFromUnix(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`format_time(from_unix(0));`, "1970-01-01T00:00:00Z"},
+		{`format_time(from_unix(1710495000));`, "2024-03-15T09:30:00Z"},
+		{`format_time(from_unix(1710495000.25));`, "2024-03-15T09:30:00.25Z"},
+		{`format_time(from_unix(-86400));`, "1969-12-31T00:00:00Z"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			var stdout bytes.Buffer
+			evaluated := testEvalWithBuiltins(tt.input, &stdout)
+			testStringObject(t, evaluated, tt.expected)
+		})
+	}
+}
+
+func TestTimeFields(t *testing.T) {
+	tests := []struct {
+		field    string
+		expected any
+	}{
+		{"year", int64(2024)},
+		{"month", int64(3)},
+		{"day", int64(15)},
+		{"hour", int64(9)},
+		{"minute", int64(30)},
+		{"second", int64(45)},
+		{"millisecond", int64(250)},
+		{"nanosecond", int64(250000000)},
+		{"weekday", "Friday"},
+		{"yday", int64(75)},
+		{"unix", int64(1710495045)},
+		{"unix_ms", int64(1710495045250)},
+		{"zone", "UTC"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.field, func(t *testing.T) {
+			var stdout bytes.Buffer
+			input := `parse_time("2024-03-15T09:30:45.25Z").` + tt.field + `;`
+			evaluated := testEvalWithBuiltins(input, &stdout)
+			switch expected := tt.expected.(type) {
+			case int64:
+				testIntegerObject(t, evaluated, expected)
+			case string:
+				testStringObject(t, evaluated, expected)
+			}
+		})
+	}
+}
+
+func TestTimeFieldsInZone(t *testing.T) {
+	var stdout bytes.Buffer
+	input := `t = in_zone(parse_time("2024-03-15T02:00:00Z"), "America/Los_Angeles");
+	[t.day, t.hour, t.zone, t.offset];`
+	evaluated := testEvalWithBuiltins(input, &stdout)
+	if evaluated.Inspect() != "[14, 19, PDT, -7h]" {
+		t.Errorf("wrong result. got=%q", evaluated.Inspect())
+	}
+}
+
+func TestParsedTimeArithmetic(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`format_time(parse_time("2024-03-15T09:30:00Z") + 90 minutes);`, "2024-03-15T11:00:00Z"},
+		{`format_time(parse_time("2024-03-01") - 1 day);`, "2024-02-29T00:00:00Z"},
+		{`str(parse_time("2024-03-15T12:00:00Z") - parse_time("2024-03-15T09:30:00Z"));`, "2h30m"},
+		{`str(parse_time("2024-03-15") < parse_time("2024-03-16"));`, "true"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			var stdout bytes.Buffer
+			evaluated := testEvalWithBuiltins(tt.input, &stdout)
+			testStringObject(t, evaluated, tt.expected)
+		})
+	}
+}
+
+func TestBuiltinTimeFunctionErrors(t *testing.T) {
+	tests := []struct {
+		input           string
+		expectedMessage string
+	}{
+		{`parse_time();`, "wrong number of arguments to parse_time: expected 1 to 3, got 0"},
+		{`parse_time(1);`, "argument to parse_time must be STRING, got INTEGER"},
+		{`parse_time("yesterday");`, `parse_time: cannot parse "yesterday" as an ISO 8601 time`},
+		{`parse_time("2024-13-01");`, `parse_time: cannot parse "2024-13-01" as an ISO 8601 time`},
+		{`parse_time("15/03/2024", "2006-01-02");`, `parse_time: cannot parse "15/03/2024" with layout "2006-01-02"`},
+		{`parse_time("2024-03-15", 1);`, "parse_time layout must be STRING, got INTEGER"},
+		{`parse_time("2024-03-15", "");`, "parse_time layout must not be empty"},
+		{`parse_time("2024-03-15", zone: "Mars/Olympus");`, `parse_time: unknown time zone "Mars/Olympus"`},
+		{`format_time("2024-03-15");`, "argument to format_time must be TIME, got STRING"},
+		{`format_time(now(), 5);`, "format_time layout must be STRING, got INTEGER"},
+		{`in_zone(now());`, "wrong number of arguments to in_zone: expected 2, got 1"},
+		{`in_zone(now(), "");`, "in_zone zone must not be empty"},
+		{`in_zone(now(), "Nowhere");`, `in_zone: unknown time zone "Nowhere"`},
+		{`from_unix("0");`, "argument to from_unix must be INTEGER or FLOAT, got STRING"},
+		{`from_unix(1e300);`, "from_unix: 1e+300 is out of range"},
+		{`now().fortnight;`, "undefined time field: fortnight"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			var stdout bytes.Buffer
+			evaluated := testEvalWithBuiltins(tt.input, &stdout)
+			testErrorObject(t, evaluated, tt.expectedMessage)
+		})
+	}
+}
@@ -0,0 +1,129 @@
+package eval
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+)
+
+// builtinTOMLParse decodes a TOML document. Tables become objects whose
+// keys keep their document order.
+// Returns Hash.
+func builtinTOMLParse(env *Environment, args ...Object) Object {
+	if err := checkArgCount("toml.parse", args, 1); err != nil {
+		return err
+	}
+	text, err := stringArgument("toml.parse", "", args[0])
+	if err != nil {
+		return err
+	}
+
+	var data map[string]any
+	meta, decodeErr := toml.Decode(text, &data)
+	if decodeErr != nil {
+		return newBuiltinError("toml.parse: %s", strings.TrimPrefix(decodeErr.Error(), "toml: "))
+	}
+
+	// The decoded maps are unordered; meta lists keys in document order.
+	order := make(map[string]int)
+	for i, key := range meta.Keys() {
+		path := strings.Join(key, tomlPathSeparator)
+		if _, seen := order[path]; !seen {
+			order[path] = i
+		}
+	}
+
+	value, err := tomlValueToObject(data, "", order)
+	if err != nil {
+		return newBuiltinError("toml.parse: %s", err.Message)
+	}
+	return value
+}
+
+// tomlPathSeparator joins the parts of a key path. Quoted TOML keys may
+// contain dots, so a dot would be ambiguous.
+const tomlPathSeparator = "\x00"
+
+// tomlValueToObject converts a decoded TOML value to an object. path is
+// the key path of the value, used to look up key order; list indexes are
+// not part of it.
+func tomlValueToObject(value any, path string, order map[string]int) (Object, *Error) {
+	switch value := value.(type) {
+	case map[string]any:
+		return tomlTableToHash(value, path, order)
+	case []map[string]any:
+		elements := make([]Object, 0, len(value))
+		for _, table := range value {
+			elem, err := tomlTableToHash(table, path, order)
+			if err != nil {
+				return nil, err
+			}
+			elements = append(elements, elem)
+		}
+		return &List{Elements: elements}, nil
+	case []any:
+		elements := make([]Object, 0, len(value))
+		for _, item := range value {
+			elem, err := tomlValueToObject(item, path, order)
+			if err != nil {
+				return nil, err
+			}
+			elements = append(elements, elem)
+		}
+		return &List{Elements: elements}, nil
+	case string:
+		return &String{Value: value}, nil
+	case int64:
+		return &Integer{Value: value}, nil
+	case float64:
+		return &Float{Value: value}, nil
+	case bool:
+		return nativeBoolToBooleanObject(value), nil
+	case time.Time:
+		return &Time{Value: value}, nil
+	default:
+		return nil, newBuiltinError("unsupported value %s of type %T", fmt.Sprint(value), value)
+	}
+}
+
+// tomlTableToHash converts a table, ordering its keys as they appear in
+// the document.
+func tomlTableToHash(table map[string]any, path string, order map[string]int) (*Hash, *Error) {
+	childPath := func(key string) string {
+		if path == "" {
+			return key
+		}
+		return path + tomlPathSeparator + key
+	}
+	position := func(key string) int {
+		if i, ok := order[childPath(key)]; ok {
+			return i
+		}
+		return len(order)
+	}
+
+	keys := make([]string, 0, len(table))
+	for key := range table {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		pi, pj := position(keys[i]), position(keys[j])
+		if pi != pj {
+			return pi < pj
+		}
+		return keys[i] < keys[j]
+	})
+
+	hash := NewHash()
+	for _, key := range keys {
+		value, err := tomlValueToObject(table[key], childPath(key), order)
+		if err != nil {
+			return nil, err
+		}
+		hash.Set(key, value)
+	}
+	return hash, nil
+}
@@ -0,0 +1,80 @@
+package eval
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestBuiltinTOMLParse(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"", "{}"},
+		{"title = \"deploy\"\nport = 80\nratio = 0.5\nenabled = true\n", "{title: deploy, port: 80, ratio: 0.5, enabled: true}"},
+		{"zeta = 1\nalpha = 2\n", "{zeta: 1, alpha: 2}"},
+		{"[lambda]\nmemory = 512\ntimeout = 30\n\n[lambda.env]\nSTAGE = \"prod\"\n", "{lambda: {memory: 512, timeout: 30, env: {STAGE: prod}}}"},
+		{"[[functions]]\nname = \"a\"\n\n[[functions]]\nname = \"b\"\nmemory = 256\n", "{functions: [{name: a}, {name: b, memory: 256}]}"},
+		{"tags = [\"x\", \"y\"]\nnested = [[1, 2], [3]]\n", "{tags: [x, y], nested: [[1, 2], [3]]}"},
+		{"point = {y = 2, x = 1}\n", "{point: {y: 2, x: 1}}"},
+		{"\"a.b\" = 1\na.c = 2\n", "{a.b: 1, a: {c: 2}}"},
+		{"at = 2024-01-02T03:04:05Z\n", "{at: 2024-01-02T03:04:05Z}"},
+		{"int = 1\nfloat = 1.0\n", "{int: 1, float: 1}"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			result := builtinTOMLParse(nil, &String{Value: tt.input})
+			if isError(result) {
+				t.Fatalf("unexpected error: %s", result.Inspect())
+			}
+			if result.Inspect() != tt.expected {
+				t.Errorf("wrong result. got=%q, want=%q", result.Inspect(), tt.expected)
+			}
+		})
+	}
+}
+
+func TestBuiltinTOMLParseTypes(t *testing.T) {
+	result := builtinTOMLParse(nil, &String{Value: "i = 1\nf = 1.0\ns = \"1\"\nd = 2024-01-02\n"})
+	hash, ok := result.(*Hash)
+	if !ok {
+		t.Fatalf("expected *Hash, got %T (%s)", result, result.Inspect())
+	}
+
+	expected := []struct {
+		key, typ string
+	}{
+		{"i", "int"},
+		{"f", "float"},
+		{"s", "string"},
+		{"d", "time"},
+	}
+	for _, tt := range expected {
+		value, _ := hash.Get(tt.key)
+		if typeName(value) != tt.typ {
+			t.Errorf("%s: wrong type. got=%s, want=%s", tt.key, typeName(value), tt.typ)
+		}
+	}
+}
+
+func TestBuiltinTOMLErrors(t *testing.T) {
+	tests := []struct {
+		input           string
+		expectedMessage string
+	}{
+		{"a = 1\nb = \n", `toml.parse: line 3 (last key "b"): expected value but found '\n' instead`},
+		{"a = 1\na = 2\n", `toml.parse: line 2 (last key "a"): Key 'a' has already been defined.`},
+		{"[t]\nx = 1\n[t]\n", `toml.parse: line 3: Key 't' has already been defined.`},
+		{"n = 99999999999999999999\n", `toml.parse: line 1 (last key "n"): 99999999999999999999 is out of range for int64`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			testErrorObject(t, builtinTOMLParse(nil, &String{Value: tt.input}), tt.expectedMessage)
+		})
+	}
+
+	var stdout bytes.Buffer
+	testErrorObject(t, testEvalWithBuiltins(`toml.parse(1);`, &stdout), "argument to toml.parse must be STRING, got INTEGER")
+}
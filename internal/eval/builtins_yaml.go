@@ -0,0 +1,339 @@
+package eval
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// builtinYAMLParse decodes a single YAML document. Mappings keep their
+// key order and become objects; merge keys (<<) and aliases are
+// resolved.
+// Returns the decoded value.
+func builtinYAMLParse(env *Environment, args ...Object) Object {
+	if err := checkArgCount("yaml.parse", args, 1); err != nil {
+		return err
+	}
+	text, err := stringArgument("yaml.parse", "", args[0])
+	if err != nil {
+		return err
+	}
+
+	value, err := decodeYAML(text)
+	if err != nil {
+		return newBuiltinError("yaml.parse: %s", err.Message)
+	}
+	return value
+}
+
+// builtinYAMLStringify encodes a value as a YAML document in block style
+// with two-space indentation.
+// Returns String.
+func builtinYAMLStringify(env *Environment, args ...Object) Object {
+	if err := checkArgCount("yaml.stringify", args, 1); err != nil {
+		return err
+	}
+
+	text, err := encodeYAML(args[0])
+	if err != nil {
+		return newBuiltinError("yaml.stringify: %s", err.Message)
+	}
+	return &String{Value: text}
+}
+
+// decodeYAML converts a YAML document to objects. Errors name the source
+// line where decoding failed.
+func decodeYAML(text string) (Object, *Error) {
+	dec := yaml.NewDecoder(strings.NewReader(text))
+
+	var doc yaml.Node
+	if err := dec.Decode(&doc); err != nil {
+		if errors.Is(err, io.EOF) {
+			return NULL, nil
+		}
+		return nil, yamlDecodeError(err, text)
+	}
+
+	var extra yaml.Node
+	if err := dec.Decode(&extra); !errors.Is(err, io.EOF) {
+		if err != nil {
+			return nil, yamlDecodeError(err, text)
+		}
+		return nil, newBuiltinError("line %d: expected a single document, found another", extra.Line)
+	}
+
+	conv := &yamlConverter{expanding: make(map[*yaml.Node]bool)}
+	return conv.object(&doc)
+}
+
+// yamlError strips the package prefix from a yaml.v3 error. Decoding
+// errors already name the line.
+func yamlError(err error) *Error {
+	return newBuiltinError("%s", strings.TrimPrefix(err.Error(), "yaml: "))
+}
+
+// yamlDecodeError is yamlError for errors from decoding text. yaml.v3
+// reports unknown anchors without a line, so the line of the first alias
+// to the anchor is added.
+func yamlDecodeError(err error, text string) *Error {
+	if rest, ok := strings.CutPrefix(err.Error(), "yaml: unknown anchor '"); ok {
+		name, _ := strings.CutSuffix(rest, "' referenced")
+		if index := strings.Index(text, "*"+name); index >= 0 {
+			line := strings.Count(text[:index], "\n") + 1
+			return newBuiltinError("line %d: unknown anchor '%s' referenced", line, name)
+		}
+	}
+	return yamlError(err)
+}
+
+// maxYAMLNodes bounds the number of values produced from one document.
+// Aliases can be nested so that a small document expands into an
+// enormous value; such documents are rejected.
+const maxYAMLNodes = 1_000_000
+
+// yamlConverter holds the state of converting one YAML document.
+type yamlConverter struct {
+	expanding map[*yaml.Node]bool // anchors whose aliases are being expanded
+	count     int                 // values produced so far
+}
+
+// object converts a decoded YAML node to an object.
+func (c *yamlConverter) object(node *yaml.Node) (Object, *Error) {
+	c.count++
+	if c.count > maxYAMLNodes {
+		return nil, newBuiltinError("line %d: document expands to more than %d values", node.Line, maxYAMLNodes)
+	}
+
+	switch node.Kind {
+	case yaml.DocumentNode:
+		if len(node.Content) == 0 {
+			return NULL, nil
+		}
+		return c.object(node.Content[0])
+	case yaml.AliasNode:
+		if c.expanding[node.Alias] {
+			return nil, newBuiltinError("line %d: alias *%s refers to itself", node.Line, node.Value)
+		}
+		c.expanding[node.Alias] = true
+		defer delete(c.expanding, node.Alias)
+		return c.object(node.Alias)
+	case yaml.SequenceNode:
+		elements := make([]Object, 0, len(node.Content))
+		for _, child := range node.Content {
+			elem, err := c.object(child)
+			if err != nil {
+				return nil, err
+			}
+			elements = append(elements, elem)
+		}
+		return &List{Elements: elements}, nil
+	case yaml.MappingNode:
+		hash := NewHash()
+		if err := c.mappingInto(hash, node, true); err != nil {
+			return nil, err
+		}
+		return hash, nil
+	default:
+		return yamlScalarToObject(node)
+	}
+}
+
+// mappingInto adds the pairs of a mapping node to hash. Merge keys are
+// expanded in place; values they bring in never replace keys set
+// explicitly in the mapping. override reports whether keys of this
+// mapping replace keys already in hash.
+func (c *yamlConverter) mappingInto(hash *Hash, node *yaml.Node, override bool) *Error {
+	if node.Kind == yaml.AliasNode {
+		if c.expanding[node.Alias] {
+			return newBuiltinError("line %d: alias *%s refers to itself", node.Line, node.Value)
+		}
+		c.expanding[node.Alias] = true
+		defer delete(c.expanding, node.Alias)
+		node = node.Alias
+	}
+	if node.Kind != yaml.MappingNode {
+		return newBuiltinError("line %d: merge value must be a mapping or list of mappings", node.Line)
+	}
+
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		keyNode, valueNode := node.Content[i], node.Content[i+1]
+
+		if keyNode.Kind == yaml.ScalarNode && keyNode.ShortTag() == "!!merge" {
+			sources := []*yaml.Node{valueNode}
+			if valueNode.Kind == yaml.SequenceNode {
+				sources = valueNode.Content
+			}
+			for _, source := range sources {
+				if err := c.mappingInto(hash, source, false); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+
+		if keyNode.Kind == yaml.AliasNode {
+			keyNode = keyNode.Alias
+		}
+		if keyNode.Kind != yaml.ScalarNode {
+			return newBuiltinError("line %d: mapping keys must be scalars", keyNode.Line)
+		}
+		if _, exists := hash.Get(keyNode.Value); exists && !override {
+			continue
+		}
+
+		value, err := c.object(valueNode)
+		if err != nil {
+			return err
+		}
+		hash.Set(keyNode.Value, value)
+	}
+	return nil
+}
+
+// yamlScalarToObject converts a scalar node according to its resolved
+// tag. Unknown tags are kept as strings.
+func yamlScalarToObject(node *yaml.Node) (Object, *Error) {
+	switch node.ShortTag() {
+	case "!!null":
+		return NULL, nil
+	case "!!bool":
+		var value bool
+		if err := node.Decode(&value); err != nil {
+			return nil, newBuiltinError("line %d: invalid bool %q", node.Line, node.Value)
+		}
+		return nativeBoolToBooleanObject(value), nil
+	case "!!int":
+		var value int64
+		if err := node.Decode(&value); err != nil {
+			return nil, newBuiltinError("line %d: integer %s overflows int64", node.Line, node.Value)
+		}
+		return &Integer{Value: value}, nil
+	case "!!float":
+		// YAML resolves integers too large for int64 as floats; treat
+		// them as errors, as json.parse does, rather than lose precision.
+		if isDecimalInteger(node.Value) {
+			return nil, newBuiltinError("line %d: integer %s overflows int64", node.Line, node.Value)
+		}
+		var value float64
+		if err := node.Decode(&value); err != nil {
+			return nil, newBuiltinError("line %d: invalid float %q", node.Line, node.Value)
+		}
+		return &Float{Value: value}, nil
+	case "!!timestamp":
+		var value time.Time
+		if err := node.Decode(&value); err != nil {
+			return nil, newBuiltinError("line %d: invalid timestamp %q", node.Line, node.Value)
+		}
+		return &Time{Value: value}, nil
+	default:
+		return &String{Value: node.Value}, nil
+	}
+}
+
+// isDecimalInteger reports whether s is an optionally signed run of
+// decimal digits and underscores.
+func isDecimalInteger(s string) bool {
+	digits := strings.TrimLeft(s, "+-")
+	if digits == "" || len(s)-len(digits) > 1 {
+		return false
+	}
+	return strings.Trim(digits, "0123456789_") == ""
+}
+
+// encodeYAML converts an object to YAML text without a trailing newline.
+func encodeYAML(obj Object) (string, *Error) {
+	node, err := objectToYAMLNode(obj, make(map[Object]bool))
+	if err != nil {
+		return "", err
+	}
+
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(node); err != nil {
+		return "", yamlError(err)
+	}
+	if err := enc.Close(); err != nil {
+		return "", yamlError(err)
+	}
+	return strings.TrimSuffix(buf.String(), "\n"), nil
+}
+
+// objectToYAMLNode builds the YAML node for an object. visiting holds
+// the lists and hashes currently being encoded, to reject cycles.
+func objectToYAMLNode(obj Object, visiting map[Object]bool) (*yaml.Node, *Error) {
+	scalar := func(tag, value string) *yaml.Node {
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: tag, Value: value}
+	}
+
+	switch obj := obj.(type) {
+	case *Null:
+		return scalar("!!null", "null"), nil
+	case *Boolean:
+		return scalar("!!bool", strconv.FormatBool(obj.Value)), nil
+	case *Integer:
+		return scalar("!!int", strconv.FormatInt(obj.Value, 10)), nil
+	case *Float:
+		switch {
+		case math.IsNaN(obj.Value):
+			return scalar("!!float", ".nan"), nil
+		case math.IsInf(obj.Value, 1):
+			return scalar("!!float", ".inf"), nil
+		case math.IsInf(obj.Value, -1):
+			return scalar("!!float", "-.inf"), nil
+		}
+		return scalar("!!float", formatJSONFloat(obj.Value)), nil
+	case *String:
+		return scalar("!!str", obj.Value), nil
+	case *Duration:
+		return scalar("!!str", obj.Inspect()), nil
+	case *Time:
+		return scalar("!!timestamp", obj.Value.Format(time.RFC3339Nano)), nil
+	case *List:
+		if visiting[obj] {
+			return nil, newBuiltinError("cannot encode cyclic LIST as YAML")
+		}
+		visiting[obj] = true
+		defer delete(visiting, obj)
+
+		node := &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq"}
+		if len(obj.Elements) == 0 {
+			node.Style = yaml.FlowStyle
+		}
+		for _, elem := range obj.Elements {
+			child, err := objectToYAMLNode(elem, visiting)
+			if err != nil {
+				return nil, err
+			}
+			node.Content = append(node.Content, child)
+		}
+		return node, nil
+	case *Hash:
+		if visiting[obj] {
+			return nil, newBuiltinError("cannot encode cyclic HASH as YAML")
+		}
+		visiting[obj] = true
+		defer delete(visiting, obj)
+
+		node := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+		if obj.Len() == 0 {
+			node.Style = yaml.FlowStyle
+		}
+		for _, pair := range obj.Pairs() {
+			child, err := objectToYAMLNode(pair.Value, visiting)
+			if err != nil {
+				return nil, err
+			}
+			node.Content = append(node.Content, scalar("!!str", pair.Key), child)
+		}
+		return node, nil
+	default:
+		return nil, newBuiltinError("cannot encode %s as YAML", obj.Type())
+	}
+}
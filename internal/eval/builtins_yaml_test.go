@@ -0,0 +1,180 @@
+package eval
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestBuiltinYAMLParse(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"", "null"},
+		{"42", "42"},
+		{"name: api\nmemory: 128\nratio: 0.5\nenabled: true\nttl: ~\n", "{name: api, memory: 128, ratio: 0.5, enabled: true, ttl: null}"},
+		{"zeta: 1\nalpha: 2\n", "{zeta: 1, alpha: 2}"},
+		{"tags: [a, b]\nlayers:\n  - name: x\n  - name: y\n", "{tags: [a, b], layers: [{name: x}, {name: y}]}"},
+		{"a: \"123\"\nb: 'true'\nc: yes\n", "{a: 123, b: true, c: yes}"},
+		{"hex: 0x1F\nunderscore: 1_000\n", "{hex: 31, underscore: 1000}"},
+		{"at: 2024-01-02T03:04:05Z\n", "{at: 2024-01-02T03:04:05Z}"},
+		{"base: &b {memory: 128, timeout: 3}\nprod:\n  <<: *b\n  memory: 512\n", "{base: {memory: 128, timeout: 3}, prod: {memory: 512, timeout: 3}}"},
+		{"a: &a {x: 1}\nb: &b {x: 2, y: 2}\nc:\n  <<: [*a, *b]\n", "{a: {x: 1}, b: {x: 2, y: 2}, c: {x: 1, y: 2}}"},
+		{"defaults: &d [1, 2]\ncopy: *d\n", "{defaults: [1, 2], copy: [1, 2]}"},
+		{"1: one\ntrue: yes\n", "{1: one, true: yes}"},
+		{"text: |\n  line one\n  line two\n", "{text: line one\nline two\n}"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			result := builtinYAMLParse(nil, &String{Value: tt.input})
+			if isError(result) {
+				t.Fatalf("unexpected error: %s", result.Inspect())
+			}
+			if result.Inspect() != tt.expected {
+				t.Errorf("wrong result. got=%q, want=%q", result.Inspect(), tt.expected)
+			}
+		})
+	}
+}
+
+func TestBuiltinYAMLParseTypes(t *testing.T) {
+	result := builtinYAMLParse(nil, &String{Value: "i: 1\nf: 1.0\ns: '1'\nt: 2024-01-02\n"})
+	hash, ok := result.(*Hash)
+	if !ok {
+		t.Fatalf("expected *Hash, got %T (%s)", result, result.Inspect())
+	}
+
+	expected := []struct {
+		key, typ string
+	}{
+		{"i", "int"},
+		{"f", "float"},
+		{"s", "string"},
+		{"t", "time"},
+	}
+	for _, tt := range expected {
+		value, _ := hash.Get(tt.key)
+		if typeName(value) != tt.typ {
+			t.Errorf("%s: wrong type. got=%s, want=%s", tt.key, typeName(value), tt.typ)
+		}
+	}
+}
+
+func TestBuiltinYAMLParseErrors(t *testing.T) {
+	tests := []struct {
+		input           string
+		expectedMessage string
+	}{
+		{"a: [1\nb: 2", "yaml.parse: line 1: did not find expected ',' or ']'"},
+		{"a:\n  b: 1\n c: 2\n", "yaml.parse: line 2: did not find expected key"},
+		{"a: 1\n---\nb: 2\n", "yaml.parse: line 2: expected a single document, found another"},
+		{"n: 1\nbig: 99999999999999999999\n", "yaml.parse: line 2: integer 99999999999999999999 overflows int64"},
+		{"a: &x [*x]\n", "yaml.parse: line 1: alias *x refers to itself"},
+		{"a: &x {<<: *x}\n", "yaml.parse: line 1: alias *x refers to itself"},
+		{"a: {<<: 1}\n", "yaml.parse: line 1: merge value must be a mapping or list of mappings"},
+		{"? [a, b]\n: 1\n", "yaml.parse: line 1: mapping keys must be scalars"},
+		{"a: 1\nb: *missing\n", `yaml.parse: line 2: unknown anchor 'missing' referenced`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			testErrorObject(t, builtinYAMLParse(nil, &String{Value: tt.input}), tt.expectedMessage)
+		})
+	}
+}
+
+func TestYAMLParseRejectsAliasExpansion(t *testing.T) {
+	// Each level doubles the size of the previous one.
+	var doc strings.Builder
+	doc.WriteString("l0: &l0 [x, x]\n")
+	for i := 1; i <= 30; i++ {
+		doc.WriteString(fmt.Sprintf("l%d: &l%d [*l%d, *l%d]\n", i, i, i-1, i-1))
+	}
+
+	result := builtinYAMLParse(nil, &String{Value: doc.String()})
+	errObj, ok := result.(*Error)
+	if !ok {
+		t.Fatalf("expected error, got %T", result)
+	}
+	if !strings.Contains(errObj.Message, "expands to more than") {
+		t.Errorf("wrong error message. got=%q", errObj.Message)
+	}
+}
+
+func TestBuiltinYAMLStringify(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`yaml.stringify(42);`, "42"},
+		{`yaml.stringify(null);`, "null"},
+		{`yaml.stringify(2.0);`, "2.0"},
+		{`yaml.stringify({z: 1, a: [1, "x"]});`, "z: 1\na:\n  - 1\n  - x"},
+		{`yaml.stringify({a: {b: {c: true}}});`, "a:\n  b:\n    c: true"},
+		{`yaml.stringify({list: [], obj: {}});`, "list: []\nobj: {}"},
+		{`yaml.stringify({n: "123", b: "true", s: "a: b"});`, "n: \"123\"\nb: \"true\"\ns: 'a: b'"},
+		{`yaml.stringify({timeout: 30s});`, "timeout: 30s"},
+		{`x = {name: "api", memory: 128, tags: ["a"], ratio: 0.5}; yaml.parse(yaml.stringify(x)) == x;`, "true"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			var stdout bytes.Buffer
+			evaluated := testEvalWithBuiltins(tt.input, &stdout)
+			if evaluated.Inspect() != tt.expected {
+				t.Errorf("wrong result. got=%q, want=%q", evaluated.Inspect(), tt.expected)
+			}
+		})
+	}
+}
+
+func TestBuiltinYAMLStringifySpecialValues(t *testing.T) {
+	cyclic := &List{}
+	cyclic.Elements = []Object{cyclic}
+
+	tests := []struct {
+		name     string
+		value    Object
+		expected string
+	}{
+		{"nan", &Float{Value: math.NaN()}, ".nan"},
+		{"infinity", &Float{Value: math.Inf(-1)}, "-.inf"},
+		{"multiline", &String{Value: "a\nb"}, "|-\n  a\n  b"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := builtinYAMLStringify(nil, tt.value)
+			if result.Inspect() != tt.expected {
+				t.Errorf("wrong result. got=%q, want=%q", result.Inspect(), tt.expected)
+			}
+		})
+	}
+
+	testErrorObject(t, builtinYAMLStringify(nil, cyclic), "yaml.stringify: cannot encode cyclic LIST as YAML")
+	testErrorObject(t, builtinYAMLStringify(nil, Builtins["print"]), "yaml.stringify: cannot encode BUILTIN as YAML")
+}
+
+func TestBuiltinYAMLErrors(t *testing.T) {
+	tests := []struct {
+		input           string
+		expectedMessage string
+	}{
+		{`yaml.parse();`, "wrong number of arguments to yaml.parse: expected 1, got 0"},
+		{`yaml.parse(1);`, "argument to yaml.parse must be STRING, got INTEGER"},
+		{`yaml.stringify(1, 2);`, "wrong number of arguments to yaml.stringify: expected 1, got 2"},
+		{`yaml.dump;`, "undefined member: yaml.dump"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			var stdout bytes.Buffer
+			evaluated := testEvalWithBuiltins(tt.input, &stdout)
+			testErrorObject(t, evaluated, tt.expectedMessage)
+		})
+	}
+}
@@ -4,16 +4,24 @@ package eval
 import (
 	"fmt"
 	"io"
+	"log/slog"
+	"slices"
 	"strings"
+
+	"github.com/boattime/awsl/internal/ast"
 )
 
 // Environment stores variable bindings for the current scope.
 // It supports nested scopes through an optional outer environment,
 // enabling lexical scoping for functions.
 type Environment struct {
-	store  map[string]Object
-	outer  *Environment
-	stdout io.Writer
+	store      map[string]Object
+	outer      *Environment
+	stdout     io.Writer
+	dir        string
+	denyWrites bool
+	logger     *slog.Logger
+	callPos    ast.Position
 }
 
 // NewEnvironment creates a new empty environment.
@@ -31,9 +39,12 @@ func NewEnvironment(stdout io.Writer) *Environment {
 // should be readable but assignments create local bindings.
 func NewEnclosedEnvironment(outer *Environment) *Environment {
 	return &Environment{
-		store:  make(map[string]Object),
-		outer:  outer,
-		stdout: outer.stdout,
+		store:      make(map[string]Object),
+		outer:      outer,
+		stdout:     outer.stdout,
+		dir:        outer.dir,
+		denyWrites: outer.denyWrites,
+		logger:     outer.logger,
 	}
 }
 
@@ -84,6 +95,19 @@ func (e *Environment) Has(name string) bool {
 	return false
 }
 
+// Names returns the names bound in this scope and every outer scope,
+// sorted and without duplicates.
+func (e *Environment) Names() []string {
+	var names []string
+	for env := e; env != nil; env = env.outer {
+		for name := range env.store {
+			names = append(names, name)
+		}
+	}
+	slices.Sort(names)
+	return slices.Compact(names)
+}
+
 // Stdout returns the stdout writer.
 func (e *Environment) Stdout() io.Writer {
 	if e.stdout != nil {
@@ -92,6 +116,49 @@ func (e *Environment) Stdout() io.Writer {
 	return nil
 }
 
+// SetDir sets the directory that relative file paths resolve against,
+// normally the directory of the running script. An empty dir means the
+// working directory.
+func (e *Environment) SetDir(dir string) {
+	e.dir = dir
+}
+
+// Dir returns the directory that relative file paths resolve against.
+func (e *Environment) Dir() string {
+	return e.dir
+}
+
+// SetDenyWrites controls whether builtins may modify the filesystem.
+func (e *Environment) SetDenyWrites(deny bool) {
+	e.denyWrites = deny
+}
+
+// DenyWrites reports whether builtins are forbidden to modify the
+// filesystem.
+func (e *Environment) DenyWrites() bool {
+	return e.denyWrites
+}
+
+// SetLogger sets the logger that the log builtins write to.
+func (e *Environment) SetLogger(logger *slog.Logger) {
+	e.logger = logger
+}
+
+// Logger returns the logger for the log builtins, writing text records
+// to stderr if none was set.
+func (e *Environment) Logger() *slog.Logger {
+	if e.logger != nil {
+		return e.logger
+	}
+	return defaultLogger
+}
+
+// CallPosition returns the position of the call to the builtin that is
+// running, or the zero Position if it is unknown.
+func (e *Environment) CallPosition() ast.Position {
+	return e.callPos
+}
+
 // Debug prints the environment chain from global (least indented) to current (most indented).
 func (e *Environment) Debug(depth *int) {
 	if e.outer != nil {
@@ -2,6 +2,7 @@ package eval
 
 import (
 	"os"
+	"strings"
 	"testing"
 )
 
@@ -121,3 +122,21 @@ func TestEnvironment_MultipleNestingLevels(t *testing.T) {
 		t.Error("level1 should not see level2 'c'")
 	}
 }
+
+func TestEnvironment_Names(t *testing.T) {
+	global := NewEnvironment(os.Stdout)
+	global.Set("b", &Integer{Value: 1})
+	global.Set("a", &Integer{Value: 2})
+
+	inner := NewEnclosedEnvironment(global)
+	inner.SetLocal("c", &Integer{Value: 3})
+	inner.SetLocal("a", &Integer{Value: 4})
+
+	names := inner.Names()
+	if strings.Join(names, ",") != "a,b,c" {
+		t.Errorf("wrong names. got=%v", names)
+	}
+	if strings.Join(global.Names(), ",") != "a,b" {
+		t.Errorf("wrong outer names. got=%v", global.Names())
+	}
+}
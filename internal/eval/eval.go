@@ -5,6 +5,13 @@ package eval
 
 import (
 	"fmt"
+	"math"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
 	"github.com/boattime/awsl/internal/ast"
 	"github.com/boattime/awsl/internal/token"
 )
@@ -29,12 +36,16 @@ func Eval(node ast.Node, env *Environment) Object {
 		return evalFunctionDeclaration(node, env)
 	case *ast.ReturnStatement:
 		return evalReturnStatement(node, env)
+	case *ast.AssertStatement:
+		return evalAssert(node, env)
 
 	// Literals
 	case *ast.IntegerLiteral:
 		return &Integer{Value: node.Value}
 	case *ast.FloatLiteral:
 		return &Float{Value: node.Value}
+	case *ast.DurationLiteral:
+		return &Duration{Value: node.Value}
 	case *ast.StringLiteral:
 		return &String{Value: node.Value}
 	case *ast.BooleanLiteral:
@@ -45,6 +56,10 @@ func Eval(node ast.Node, env *Environment) Object {
 		return evalListLiteral(node, env)
 	case *ast.ObjectLiteral:
 		return evalObjectLiteral(node, env)
+	case *ast.ListComprehension:
+		return evalListComprehension(node, env)
+	case *ast.HashComprehension:
+		return evalHashComprehension(node, env)
 
 	// Expressions
 	case *ast.Identifier:
@@ -59,6 +74,8 @@ func Eval(node ast.Node, env *Environment) Object {
 		return Eval(node.Expression, env)
 	case *ast.IndexExpression:
 		return evalIndexExpression(node, env)
+	case *ast.MemberExpression:
+		return evalMemberExpression(node, env)
 	}
 
 	pos := node.Pos()
@@ -111,14 +128,19 @@ func evalBlock(node *ast.BlockStatement, env *Environment) Object {
 	return result
 }
 
-// evalIf evaluates an if statement.
+// evalIf evaluates an if statement. The condition must be a boolean.
 func evalIf(node *ast.IfStatement, env *Environment) Object {
 	condition := Eval(node.Condition, env)
 	if isError(condition) {
 		return condition
 	}
 
-	if condition.(*Boolean).Value {
+	value, ok := condition.(*Boolean)
+	if !ok {
+		pos := node.Condition.Pos()
+		return newError(pos.Line, pos.Column, "condition must be BOOLEAN, got %s", condition.Type())
+	}
+	if value.Value {
 		return Eval(node.Consequence, env)
 	} else if node.Alternative != nil {
 		return Eval(node.Alternative, env)
@@ -134,7 +156,7 @@ func evalFor(node *ast.ForStatement, env *Environment) Object {
 		return iterable
 	}
 
-	list, ok := iterable.(*List)
+	elements, ok := iterationElements(iterable)
 	if !ok {
 		pos := node.Pos()
 		return newError(pos.Line, pos.Column, "cannot iterate over %s", iterable.Type())
@@ -142,7 +164,7 @@ func evalFor(node *ast.ForStatement, env *Environment) Object {
 
 	loopEnv := NewEnclosedEnvironment(env)
 
-	for _, elem := range list.Elements {
+	for _, elem := range elements {
 		loopEnv.SetLocal(node.Iterator.Value, elem)
 
 		result := Eval(node.Body, loopEnv)
@@ -179,6 +201,31 @@ func evalReturnStatement(node *ast.ReturnStatement, env *Environment) Object {
 	return &ReturnValue{Value: val}
 }
 
+// evalAssert evaluates an assert statement. A falsy condition is an
+// AssertionError naming the condition's source text and, if given, the
+// message, which is only evaluated when the assertion fails.
+func evalAssert(node *ast.AssertStatement, env *Environment) Object {
+	condition := Eval(node.Condition, env)
+	if isError(condition) {
+		return condition
+	}
+	if isTruthy(condition) {
+		return NULL
+	}
+
+	pos := node.Pos()
+	err := newError(pos.Line, pos.Column, "assertion failed: %s", node.Condition.String())
+	err.Category = AssertionError
+	if node.Message != nil {
+		message := Eval(node.Message, env)
+		if isError(message) {
+			return message
+		}
+		err.Message += ": " + message.Inspect()
+	}
+	return err
+}
+
 // evalIdentifier looks up a variable in the environment.
 func evalIdentifier(node *ast.Identifier, env *Environment) Object {
 	val, ok := env.Get(node.Value)
@@ -195,15 +242,96 @@ func evalCallExpression(node *ast.CallExpression, env *Environment) Object {
 	if isError(function) {
 		return function
 	}
+	if namespace, ok := function.(*Namespace); ok && namespace.Call != nil {
+		function = namespace.Call
+	}
 
 	args, err := evalArguments(node.Arguments, env)
 	if err != nil {
 		return err
 	}
 
+	if hasNamedArguments(node.Arguments) {
+		args, err = bindNamedArguments(function, node.Arguments, args, node.Pos())
+		if err != nil {
+			return err
+		}
+	}
+
 	return applyFunction(env, function, args, node.Pos())
 }
 
+// hasNamedArguments reports whether any argument is passed by name.
+func hasNamedArguments(arguments []ast.Argument) bool {
+	for _, arg := range arguments {
+		if arg.Name != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// bindNamedArguments reorders evaluated arguments into parameter order.
+// Positional arguments come first; each named argument is placed at the
+// index of the parameter with that name. Parameters left unfilled are an
+// error for user functions and NULL for builtins.
+func bindNamedArguments(fn Object, arguments []ast.Argument, values []Object, pos ast.Position) ([]Object, *Error) {
+	var params []string
+	var name string
+	switch function := fn.(type) {
+	case *Function:
+		name = "function"
+		for _, param := range function.Parameters {
+			params = append(params, param.Value)
+		}
+	case *Builtin:
+		name = function.Name
+		params = function.Params
+	default:
+		return nil, newError(pos.Line, pos.Column, "not a function: %s", fn.Type())
+	}
+
+	bound := make([]Object, 0, len(params))
+	seenNamed := false
+	for i, arg := range arguments {
+		if arg.Name == nil {
+			if seenNamed {
+				return nil, newError(arg.Value.Pos().Line, arg.Value.Pos().Column,
+					"positional argument after named argument")
+			}
+			bound = append(bound, values[i])
+			continue
+		}
+		seenNamed = true
+
+		index := slices.Index(params, arg.Name.Value)
+		if index < 0 {
+			return nil, newError(arg.Name.Token.Line, arg.Name.Token.Column,
+				"unknown argument %q to %s", arg.Name.Value, name)
+		}
+		for len(bound) <= index {
+			bound = append(bound, nil)
+		}
+		if bound[index] != nil {
+			return nil, newError(arg.Name.Token.Line, arg.Name.Token.Column,
+				"argument %q given more than once", arg.Name.Value)
+		}
+		bound[index] = values[i]
+	}
+
+	for i, value := range bound {
+		if value != nil {
+			continue
+		}
+		if _, ok := fn.(*Function); ok {
+			return nil, newError(pos.Line, pos.Column, "missing argument %q", params[i])
+		}
+		bound[i] = NULL
+	}
+
+	return bound, nil
+}
+
 // evalArguments evaluates a list of arguments left to right.
 func evalArguments(arguments []ast.Argument, env *Environment) ([]Object, *Error) {
 	result := make([]Object, len(arguments))
@@ -221,6 +349,12 @@ func evalArguments(arguments []ast.Argument, env *Environment) ([]Object, *Error
 
 // applyFunction calls a function with the given arguments.
 func applyFunction(env *Environment, fn Object, args []Object, pos ast.Position) Object {
+	// A callable namespace such as log is passed to builtins as a callback
+	// by its name
+	if namespace, ok := fn.(*Namespace); ok && namespace.Call != nil {
+		fn = namespace.Call
+	}
+
 	switch function := fn.(type) {
 	case *Function:
 		if len(args) != len(function.Parameters) {
@@ -231,7 +365,17 @@ func applyFunction(env *Environment, fn Object, args []Object, pos ast.Position)
 		evaluated := Eval(function.Body, extendedEnv)
 		return unwrapReturnValue(evaluated)
 	case *Builtin:
-		return function.Fn(env, args...)
+		// Callbacks run by builtins have no position of their own and
+		// keep the position of the outer call
+		if env != nil && pos.Line > 0 {
+			env.callPos = pos
+		}
+		result := function.Fn(env, args...)
+		if err, ok := result.(*Error); ok && err.Line == 0 {
+			err.Line = pos.Line
+			err.Column = pos.Column
+		}
+		return result
 	default:
 		return newError(pos.Line, pos.Column, "not a function: %s", fn.Type())
 	}
@@ -292,9 +436,14 @@ func evalBangOperator(right Object) Object {
 func evalMinusPrefixOperator(right Object, pos ast.Position) Object {
 	switch right := right.(type) {
 	case *Integer:
+		if right.Value == math.MinInt64 {
+			return newError(pos.Line, pos.Column, "integer overflow: -(%d)", right.Value)
+		}
 		return &Integer{Value: -right.Value}
 	case *Float:
 		return &Float{Value: -right.Value}
+	case *Duration:
+		return &Duration{Value: -right.Value}
 	default:
 		return newError(pos.Line, pos.Column, "unknown operator: -%s", right.Type())
 	}
@@ -316,16 +465,32 @@ func evalInfixExpression(node *ast.InfixExpression, env *Environment) Object {
 	op := node.Token.Type
 
 	switch {
+	case op == token.IN:
+		return evalInExpression(node.Operator, left, right, pos)
+	case op == token.NOT:
+		result := evalInExpression(node.Operator, left, right, pos)
+		if isError(result) {
+			return result
+		}
+		return evalBangOperator(result)
+	case op == token.MATCH:
+		return evalMatchExpression(left, right, pos)
 	case left.Type() == INTEGER_OBJ && right.Type() == INTEGER_OBJ:
 		return evalIntegerInfixExpression(op, left, right, pos)
 	case left.Type() == FLOAT_OBJ && right.Type() == FLOAT_OBJ:
 		return evalFloatInfixExpression(op, left, right, pos)
 	case left.Type() == STRING_OBJ && right.Type() == STRING_OBJ:
 		return evalStringInfixExpression(op, left, right, pos)
+	case left.Type() == DURATION_OBJ && right.Type() == DURATION_OBJ:
+		return evalDurationInfixExpression(op, left, right, pos)
+	case left.Type() == TIME_OBJ && right.Type() == TIME_OBJ:
+		return evalTimeInfixExpression(op, left, right, pos)
+	case isArithmeticOperator(op) && (left.Type() == DURATION_OBJ || right.Type() == DURATION_OBJ):
+		return evalDurationArithmetic(op, left, right, pos)
 	case op == token.EQ:
-		return nativeBoolToBooleanObject(left == right)
+		return nativeBoolToBooleanObject(objectsEqual(left, right))
 	case op == token.NOT_EQ:
-		return nativeBoolToBooleanObject(left != right)
+		return nativeBoolToBooleanObject(!objectsEqual(left, right))
 	case op == token.OR:
 		return nativeBoolToBooleanObject(isTruthy(left) || isTruthy(right))
 	case op == token.AND:
@@ -344,15 +509,30 @@ func evalIntegerInfixExpression(op token.TokenType, left, right Object, pos ast.
 
 	switch op {
 	case token.PLUS:
-		return &Integer{Value: leftVal + rightVal}
+		sum := leftVal + rightVal
+		if (rightVal > 0 && sum < leftVal) || (rightVal < 0 && sum > leftVal) {
+			return newError(pos.Line, pos.Column, "integer overflow: %d + %d", leftVal, rightVal)
+		}
+		return &Integer{Value: sum}
 	case token.MINUS:
-		return &Integer{Value: leftVal - rightVal}
+		diff := leftVal - rightVal
+		if (rightVal < 0 && diff < leftVal) || (rightVal > 0 && diff > leftVal) {
+			return newError(pos.Line, pos.Column, "integer overflow: %d - %d", leftVal, rightVal)
+		}
+		return &Integer{Value: diff}
 	case token.ASTERISK:
-		return &Integer{Value: leftVal * rightVal}
+		product := leftVal * rightVal
+		if leftVal != 0 && (product/leftVal != rightVal || (leftVal == -1 && rightVal == math.MinInt64)) {
+			return newError(pos.Line, pos.Column, "integer overflow: %d * %d", leftVal, rightVal)
+		}
+		return &Integer{Value: product}
 	case token.SLASH:
 		if rightVal == 0 {
 			return newError(pos.Line, pos.Column, "division by zero")
 		}
+		if leftVal == math.MinInt64 && rightVal == -1 {
+			return newError(pos.Line, pos.Column, "integer overflow: %d / %d", leftVal, rightVal)
+		}
 		return &Integer{Value: leftVal / rightVal}
 	case token.LT:
 		return nativeBoolToBooleanObject(leftVal < rightVal)
@@ -422,6 +602,298 @@ func evalStringInfixExpression(op token.TokenType, left, right Object, pos ast.P
 	}
 }
 
+// evalDurationInfixExpression evaluates binary operators on durations.
+// Dividing two durations yields their ratio as a float.
+func evalDurationInfixExpression(op token.TokenType, left, right Object, pos ast.Position) Object {
+	leftVal := left.(*Duration).Value
+	rightVal := right.(*Duration).Value
+
+	switch op {
+	case token.PLUS:
+		sum := leftVal + rightVal
+		if (sum > leftVal) != (rightVal > 0) {
+			return newError(pos.Line, pos.Column, "duration overflow: %s + %s", left.Inspect(), right.Inspect())
+		}
+		return &Duration{Value: sum}
+	case token.MINUS:
+		diff := leftVal - rightVal
+		if (diff < leftVal) != (rightVal > 0) {
+			return newError(pos.Line, pos.Column, "duration overflow: %s - %s", left.Inspect(), right.Inspect())
+		}
+		return &Duration{Value: diff}
+	case token.SLASH:
+		if rightVal == 0 {
+			return newError(pos.Line, pos.Column, "division by zero")
+		}
+		return &Float{Value: float64(leftVal) / float64(rightVal)}
+	case token.LT:
+		return nativeBoolToBooleanObject(leftVal < rightVal)
+	case token.GT:
+		return nativeBoolToBooleanObject(leftVal > rightVal)
+	case token.LTE:
+		return nativeBoolToBooleanObject(leftVal <= rightVal)
+	case token.GTE:
+		return nativeBoolToBooleanObject(leftVal >= rightVal)
+	case token.EQ:
+		return nativeBoolToBooleanObject(leftVal == rightVal)
+	case token.NOT_EQ:
+		return nativeBoolToBooleanObject(leftVal != rightVal)
+	default:
+		return newError(pos.Line, pos.Column, "unknown operator: DURATION %s DURATION", op)
+	}
+}
+
+// evalTimeInfixExpression evaluates binary operators on times.
+// Subtracting two times yields the duration between them.
+func evalTimeInfixExpression(op token.TokenType, left, right Object, pos ast.Position) Object {
+	leftVal := left.(*Time).Value
+	rightVal := right.(*Time).Value
+
+	switch op {
+	case token.MINUS:
+		return &Duration{Value: leftVal.Sub(rightVal)}
+	case token.LT:
+		return nativeBoolToBooleanObject(leftVal.Before(rightVal))
+	case token.GT:
+		return nativeBoolToBooleanObject(leftVal.After(rightVal))
+	case token.LTE:
+		return nativeBoolToBooleanObject(!leftVal.After(rightVal))
+	case token.GTE:
+		return nativeBoolToBooleanObject(!leftVal.Before(rightVal))
+	case token.EQ:
+		return nativeBoolToBooleanObject(leftVal.Equal(rightVal))
+	case token.NOT_EQ:
+		return nativeBoolToBooleanObject(!leftVal.Equal(rightVal))
+	default:
+		return newError(pos.Line, pos.Column, "unknown operator: TIME %s TIME", op)
+	}
+}
+
+// evalDurationArithmetic evaluates arithmetic mixing a duration with a
+// time or a number.
+// Supports: time +/- duration, duration + time, duration * number,
+// number * duration, duration / number.
+func evalDurationArithmetic(op token.TokenType, left, right Object, pos ast.Position) Object {
+	switch {
+	case left.Type() == TIME_OBJ && (op == token.PLUS || op == token.MINUS):
+		d := right.(*Duration).Value
+		if op == token.MINUS {
+			d = -d
+		}
+		return &Time{Value: left.(*Time).Value.Add(d)}
+	case right.Type() == TIME_OBJ && op == token.PLUS:
+		return &Time{Value: right.(*Time).Value.Add(left.(*Duration).Value)}
+	case left.Type() == DURATION_OBJ && op == token.ASTERISK:
+		return scaleDuration(left.(*Duration), right, pos)
+	case right.Type() == DURATION_OBJ && op == token.ASTERISK:
+		return scaleDuration(right.(*Duration), left, pos)
+	case left.Type() == DURATION_OBJ && op == token.SLASH:
+		return divideDuration(left.(*Duration), right, pos)
+	}
+
+	if left.Type() != right.Type() {
+		return newError(pos.Line, pos.Column, "type mismatch: %s %s %s", left.Type(), op, right.Type())
+	}
+	return newError(pos.Line, pos.Column, "unknown operator: %s %s %s", left.Type(), op, right.Type())
+}
+
+// scaleDuration multiplies a duration by an integer or float factor.
+func scaleDuration(d *Duration, factor Object, pos ast.Position) Object {
+	var scaled float64
+	switch factor := factor.(type) {
+	case *Integer:
+		product := int64(d.Value) * factor.Value
+		if factor.Value != 0 && product/factor.Value != int64(d.Value) {
+			return newError(pos.Line, pos.Column, "duration overflow: %s * %d", d.Inspect(), factor.Value)
+		}
+		return &Duration{Value: time.Duration(product)}
+	case *Float:
+		scaled = float64(d.Value) * factor.Value
+	default:
+		return newError(pos.Line, pos.Column, "type mismatch: DURATION * %s", factor.Type())
+	}
+
+	if math.IsNaN(scaled) || scaled >= math.MaxInt64 || scaled < math.MinInt64 {
+		return newError(pos.Line, pos.Column, "duration overflow: %s * %s", d.Inspect(), factor.Inspect())
+	}
+	return &Duration{Value: time.Duration(math.Round(scaled))}
+}
+
+// divideDuration divides a duration by an integer or float divisor.
+func divideDuration(d *Duration, divisor Object, pos ast.Position) Object {
+	switch divisor := divisor.(type) {
+	case *Integer:
+		if divisor.Value == 0 {
+			return newError(pos.Line, pos.Column, "division by zero")
+		}
+		return &Duration{Value: d.Value / time.Duration(divisor.Value)}
+	case *Float:
+		if divisor.Value == 0 {
+			return newError(pos.Line, pos.Column, "division by zero")
+		}
+		return scaleDuration(d, &Float{Value: 1 / divisor.Value}, pos)
+	default:
+		return newError(pos.Line, pos.Column, "type mismatch: DURATION / %s", divisor.Type())
+	}
+}
+
+// isArithmeticOperator reports whether op is +, -, * or /.
+func isArithmeticOperator(op token.TokenType) bool {
+	return op == token.PLUS || op == token.MINUS || op == token.ASTERISK || op == token.SLASH
+}
+
+// evalInExpression evaluates membership tests.
+// Supports: value in list, string in hash (key existence),
+// and string in string (substring).
+func evalInExpression(operator string, left, right Object, pos ast.Position) Object {
+	switch right := right.(type) {
+	case *List:
+		for _, elem := range right.Elements {
+			if objectsEqual(left, elem) {
+				return TRUE
+			}
+		}
+		return FALSE
+	case *Hash:
+		key, ok := left.(*String)
+		if !ok {
+			return newError(pos.Line, pos.Column, "type mismatch: %s %s %s", left.Type(), operator, right.Type())
+		}
+		_, exists := right.Get(key.Value)
+		return nativeBoolToBooleanObject(exists)
+	case *String:
+		sub, ok := left.(*String)
+		if !ok {
+			return newError(pos.Line, pos.Column, "type mismatch: %s %s %s", left.Type(), operator, right.Type())
+		}
+		return nativeBoolToBooleanObject(strings.Contains(right.Value, sub.Value))
+	default:
+		return newError(pos.Line, pos.Column, "unknown operator: %s %s %s", left.Type(), operator, right.Type())
+	}
+}
+
+// evalMatchExpression evaluates string =~ pattern, where the pattern is
+// a string or a compiled regex.
+func evalMatchExpression(left, right Object, pos ast.Position) Object {
+	s, ok := left.(*String)
+	if !ok {
+		return newError(pos.Line, pos.Column, "left side of =~ must be STRING, got %s", left.Type())
+	}
+	if right.Type() != STRING_OBJ && right.Type() != REGEX_OBJ {
+		return newError(pos.Line, pos.Column, "right side of =~ must be STRING or REGEX, got %s", right.Type())
+	}
+
+	re, err := regexArgument("=~", right)
+	if err != nil {
+		return newError(pos.Line, pos.Column, "%s", err.Message)
+	}
+	return nativeBoolToBooleanObject(re.MatchString(s.Value))
+}
+
+// objectsEqual reports whether two objects are equal.
+// Primitives, durations and times compare by value; lists compare element
+// by element and hashes key by key, regardless of key order. Functions
+// compare by identity.
+func objectsEqual(left, right Object) bool {
+	switch left := left.(type) {
+	case *Integer:
+		r, ok := right.(*Integer)
+		return ok && left.Value == r.Value
+	case *Float:
+		r, ok := right.(*Float)
+		return ok && left.Value == r.Value
+	case *String:
+		r, ok := right.(*String)
+		return ok && left.Value == r.Value
+	case *Boolean:
+		r, ok := right.(*Boolean)
+		return ok && left.Value == r.Value
+	case *Null:
+		_, ok := right.(*Null)
+		return ok
+	case *Duration:
+		r, ok := right.(*Duration)
+		return ok && left.Value == r.Value
+	case *Time:
+		r, ok := right.(*Time)
+		return ok && left.Value.Equal(r.Value)
+	case *List:
+		r, ok := right.(*List)
+		if !ok || len(left.Elements) != len(r.Elements) {
+			return false
+		}
+		for i, elem := range left.Elements {
+			if !objectsEqual(elem, r.Elements[i]) {
+				return false
+			}
+		}
+		return true
+	case *Hash:
+		r, ok := right.(*Hash)
+		if !ok || left.Len() != r.Len() {
+			return false
+		}
+		for _, pair := range left.Pairs() {
+			other, exists := r.Get(pair.Key)
+			if !exists || !objectsEqual(pair.Value, other) {
+				return false
+			}
+		}
+		return true
+	default:
+		return left == right
+	}
+}
+
+// hashKey returns a string that is identical for objects that are equal
+// according to objectsEqual, so values can be used as map keys when
+// deduplicating or grouping. It reports false for values that cannot be
+// hashed, such as functions.
+func hashKey(obj Object) (string, bool) {
+	switch obj := obj.(type) {
+	case *Integer:
+		return "i:" + strconv.FormatInt(obj.Value, 10), true
+	case *Float:
+		if obj.Value == 0 {
+			return "f:0", true // 0.0 and -0.0 are equal
+		}
+		return "f:" + strconv.FormatFloat(obj.Value, 'g', -1, 64), true
+	case *String:
+		return "s:" + strconv.Quote(obj.Value), true
+	case *Boolean:
+		return "b:" + strconv.FormatBool(obj.Value), true
+	case *Null:
+		return "n", true
+	case *Duration:
+		return "d:" + strconv.FormatInt(int64(obj.Value), 10), true
+	case *Time:
+		return "t:" + obj.Value.UTC().Format(time.RFC3339Nano), true
+	case *List:
+		parts := make([]string, len(obj.Elements))
+		for i, elem := range obj.Elements {
+			key, ok := hashKey(elem)
+			if !ok {
+				return "", false
+			}
+			parts[i] = key
+		}
+		return "[" + strings.Join(parts, ",") + "]", true
+	case *Hash:
+		parts := make([]string, 0, obj.Len())
+		for _, pair := range obj.Pairs() {
+			key, ok := hashKey(pair.Value)
+			if !ok {
+				return "", false
+			}
+			parts = append(parts, strconv.Quote(pair.Key)+":"+key)
+		}
+		sort.Strings(parts) // key order does not affect equality
+		return "{" + strings.Join(parts, ",") + "}", true
+	default:
+		return "", false
+	}
+}
+
 // evalIndexExpression evaluates index access expressions.
 // Supports: list[int], string[int]
 func evalIndexExpression(node *ast.IndexExpression, env *Environment) Object {
@@ -504,6 +976,43 @@ func evalHashIndexExpression(left, index Object) Object {
 	return val
 }
 
+// evalMemberExpression evaluates member access expressions.
+// Supports: hash.key, returning NULL if the key doesn't exist,
+// namespace.member, where an unknown member is an error, and time
+// components such as t.year.
+func evalMemberExpression(node *ast.MemberExpression, env *Environment) Object {
+	object := Eval(node.Object, env)
+	if isError(object) {
+		return object
+	}
+
+	switch object := object.(type) {
+	case *Hash:
+		val, ok := object.Get(node.Member.Value)
+		if !ok {
+			return NULL
+		}
+		return val
+	case *Namespace:
+		member, ok := object.Members[node.Member.Value]
+		if !ok {
+			pos := node.Pos()
+			return newError(pos.Line, pos.Column, "undefined member: %s.%s", object.Name, node.Member.Value)
+		}
+		return member
+	case *Time:
+		field, ok := timeField(object, node.Member.Value)
+		if !ok {
+			pos := node.Pos()
+			return newError(pos.Line, pos.Column, "undefined time field: %s", node.Member.Value)
+		}
+		return field
+	default:
+		pos := node.Pos()
+		return newError(pos.Line, pos.Column, "member access not supported: %s.%s", object.Type(), node.Member.Value)
+	}
+}
+
 // nativeBoolToBooleanObject converts a Go bool to the appropriate singleton.
 func nativeBoolToBooleanObject(value bool) *Boolean {
 	if value {
@@ -528,8 +1037,9 @@ func evalListLiteral(node *ast.ListLiteral, env *Environment) Object {
 }
 
 // evalObjectLiteral evaluates an object literal.
+// Keys keep the order in which they are written.
 func evalObjectLiteral(node *ast.ObjectLiteral, env *Environment) Object {
-	pairs := make(map[string]Object)
+	hash := NewHash()
 
 	for _, pair := range node.Pairs {
 		key := pair.Key.Value
@@ -539,10 +1049,137 @@ func evalObjectLiteral(node *ast.ObjectLiteral, env *Environment) Object {
 			return value
 		}
 
-		pairs[key] = value
+		hash.Set(key, value)
+	}
+
+	return hash
+}
+
+// evalListComprehension evaluates a list comprehension.
+// The comprehension variable is bound in an enclosed environment so it
+// never leaks into, or overwrites, the surrounding scope.
+func evalListComprehension(node *ast.ListComprehension, env *Environment) Object {
+	items, errObj := evalComprehensionIterable(node.Iterable, env)
+	if errObj != nil {
+		return errObj
+	}
+
+	compEnv := NewEnclosedEnvironment(env)
+	elements := []Object{}
+
+	for _, elem := range items {
+		compEnv.SetLocal(node.Iterator.Value, elem)
+
+		keep := evalComprehensionCondition(node.Condition, compEnv)
+		if isError(keep) {
+			return keep
+		}
+		if keep == FALSE {
+			continue
+		}
+
+		evaluated := Eval(node.Element, compEnv)
+		if isError(evaluated) {
+			return evaluated
+		}
+		elements = append(elements, evaluated)
+	}
+
+	return &List{Elements: elements}
+}
+
+// evalHashComprehension evaluates a hash comprehension.
+// Keys must evaluate to strings; later keys overwrite earlier ones but
+// keep the position where the key first appeared.
+func evalHashComprehension(node *ast.HashComprehension, env *Environment) Object {
+	items, errObj := evalComprehensionIterable(node.Iterable, env)
+	if errObj != nil {
+		return errObj
+	}
+
+	compEnv := NewEnclosedEnvironment(env)
+	hash := NewHash()
+
+	for _, elem := range items {
+		compEnv.SetLocal(node.Iterator.Value, elem)
+
+		keep := evalComprehensionCondition(node.Condition, compEnv)
+		if isError(keep) {
+			return keep
+		}
+		if keep == FALSE {
+			continue
+		}
+
+		key := Eval(node.Key, compEnv)
+		if isError(key) {
+			return key
+		}
+
+		keyStr, ok := key.(*String)
+		if !ok {
+			pos := node.Key.Pos()
+			return newError(pos.Line, pos.Column, "hash key must be STRING, got %s", key.Type())
+		}
+
+		value := Eval(node.Value, compEnv)
+		if isError(value) {
+			return value
+		}
+
+		hash.Set(keyStr.Value, value)
+	}
+
+	return hash
+}
+
+// evalComprehensionIterable evaluates the collection of a comprehension
+// and returns the values to iterate, matching the for statement.
+func evalComprehensionIterable(node ast.Expression, env *Environment) ([]Object, Object) {
+	iterable := Eval(node, env)
+	if isError(iterable) {
+		return nil, iterable
+	}
+
+	elements, ok := iterationElements(iterable)
+	if !ok {
+		pos := node.Pos()
+		return nil, newError(pos.Line, pos.Column, "cannot iterate over %s", iterable.Type())
+	}
+
+	return elements, nil
+}
+
+// iterationElements returns the values a for loop or comprehension visits:
+// the elements of a list, or the keys of a hash in insertion order.
+func iterationElements(iterable Object) ([]Object, bool) {
+	switch iterable := iterable.(type) {
+	case *List:
+		return iterable.Elements, true
+	case *Hash:
+		keys := make([]Object, iterable.Len())
+		for i, key := range iterable.Keys() {
+			keys[i] = &String{Value: key}
+		}
+		return keys, true
+	default:
+		return nil, false
+	}
+}
+
+// evalComprehensionCondition evaluates the optional if clause of a
+// comprehension. Returns TRUE when there is no condition.
+func evalComprehensionCondition(condition ast.Expression, env *Environment) Object {
+	if condition == nil {
+		return TRUE
+	}
+
+	result := Eval(condition, env)
+	if isError(result) {
+		return result
 	}
 
-	return &Hash{Pairs: pairs}
+	return nativeBoolToBooleanObject(isTruthy(result))
 }
 
 // newError creates a new Error object with position information.
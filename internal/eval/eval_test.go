@@ -1,8 +1,10 @@
 package eval
 
 import (
+	"math"
 	"os"
 	"testing"
+	"time"
 
 	"github.com/boattime/awsl/internal/lexer"
 	"github.com/boattime/awsl/internal/parser"
@@ -17,6 +19,12 @@ func testEval(input string) Object {
 	return Eval(program, env)
 }
 
+// testHashValue returns the value stored under key, or nil if missing.
+func testHashValue(hash *Hash, key string) Object {
+	value, _ := hash.Get(key)
+	return value
+}
+
 // testIntegerObject checks that obj is an Integer with the expected value.
 func testIntegerObject(t *testing.T, obj Object, expected int64) bool {
 	t.Helper()
@@ -277,6 +285,66 @@ func TestCallExpressionArgumentError(t *testing.T) {
 	testErrorObject(t, result, "undefined variable: x")
 }
 
+func TestCallExpressionNamedArguments(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`fn sub(a, b) { return a - b; } sub(b: 1, a: 10);`, "9"},
+		{`fn sub(a, b) { return a - b; } sub(10, b: 1);`, "9"},
+		{`fn f(a, b, c) { return [a, b, c]; } f(1, c: 3, b: 2);`, "[1, 2, 3]"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			evaluated := testEval(tt.input)
+			if evaluated.Inspect() != tt.expected {
+				t.Errorf("wrong result. got=%s, want=%s", evaluated.Inspect(), tt.expected)
+			}
+		})
+	}
+}
+
+func TestCallExpressionNamedArgumentErrors(t *testing.T) {
+	tests := []struct {
+		input           string
+		expectedMessage string
+	}{
+		{`fn f(a, b) { return a; } f(a: 1, 2);`, "positional argument after named argument"},
+		{`fn f(a, b) { return a; } f(1, c: 2);`, `unknown argument "c" to function`},
+		{`fn f(a, b) { return a; } f(1, a: 2);`, `argument "a" given more than once`},
+		{`fn f(a, b) { return a; } f(b: 2);`, `missing argument "a"`},
+		{`fn f(a) { return a; } f(a: 1, a: 2);`, `argument "a" given more than once`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			evaluated := testEval(tt.input)
+			testErrorObject(t, evaluated, tt.expectedMessage)
+		})
+	}
+}
+
+func TestCallExpressionNamedArgumentsBuiltin(t *testing.T) {
+	env := NewEnvironment(os.Stdout)
+	env.Set("pair", &Builtin{
+		Name:   "pair",
+		Params: []string{"first", "second", "third"},
+		Fn: func(env *Environment, args ...Object) Object {
+			return &List{Elements: args}
+		},
+	})
+
+	l := lexer.New(`pair(third: 3, first: 1);`)
+	p := parser.New(l)
+	program := p.ParseProgram()
+	result := Eval(program, env)
+
+	if result.Inspect() != "[1, null, 3]" {
+		t.Errorf("wrong result. got=%s, want=[1, null, 3]", result.Inspect())
+	}
+}
+
 func TestMinusPrefixOperator(t *testing.T) {
 	tests := []struct {
 		input    string
@@ -341,6 +409,52 @@ func TestIntegerArithmetic(t *testing.T) {
 	}
 }
 
+func TestIntegerOverflowError(t *testing.T) {
+	tests := []struct {
+		input           string
+		expectedMessage string
+	}{
+		{"9223372036854775807 + 1;", "integer overflow: 9223372036854775807 + 1"},
+		{"-9223372036854775807 - 2;", "integer overflow: -9223372036854775807 - 2"},
+		{"-9223372036854775807 - 1 - 1;", "integer overflow: -9223372036854775808 - 1"},
+		{"5 - -9223372036854775807;", "integer overflow: 5 - -9223372036854775807"},
+		{"4611686018427387904 * 2;", "integer overflow: 4611686018427387904 * 2"},
+		{"0x7fffffffffffffff * -2;", "integer overflow: 9223372036854775807 * -2"},
+		{"min = -9223372036854775807 - 1; min * -1;", "integer overflow: -9223372036854775808 * -1"},
+		{"min = -9223372036854775807 - 1; -1 * min;", "integer overflow: -1 * -9223372036854775808"},
+		{"min = -9223372036854775807 - 1; min / -1;", "integer overflow: -9223372036854775808 / -1"},
+		{"min = -9223372036854775807 - 1; -min;", "integer overflow: -(-9223372036854775808)"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			evaluated := testEval(tt.input)
+			testErrorObject(t, evaluated, tt.expectedMessage)
+		})
+	}
+}
+
+func TestIntegerArithmeticNearLimits(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected int64
+	}{
+		{"9223372036854775806 + 1;", 9223372036854775807},
+		{"-9223372036854775807 - 1;", -9223372036854775808},
+		{"4611686018427387903 * 2;", 9223372036854775806},
+		{"-4611686018427387904 * 2;", -9223372036854775808},
+		{"1_000_000 * 1_000_000;", 1000000000000},
+		{"0xFF + 1;", 256},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			evaluated := testEval(tt.input)
+			testIntegerObject(t, evaluated, tt.expected)
+		})
+	}
+}
+
 func TestFloatArithmetic(t *testing.T) {
 	tests := []struct {
 		input    string
@@ -480,6 +594,194 @@ func TestNullComparison(t *testing.T) {
 	}
 }
 
+func TestDurationArithmetic(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected time.Duration
+	}{
+		{"30 days;", 30 * 24 * time.Hour},
+		{"1h + 30m;", 90 * time.Minute},
+		{"1h - 90m;", -30 * time.Minute},
+		{"2h30m * 2;", 5 * time.Hour},
+		{"3 * 10 min;", 30 * time.Minute},
+		{"1.5 * 1h;", 90 * time.Minute},
+		{"1h * 0.25;", 15 * time.Minute},
+		{"1h / 4;", 15 * time.Minute},
+		{"1h / 0.5;", 2 * time.Hour},
+		{"-5 min;", -5 * time.Minute},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			evaluated := testEval(tt.input)
+			result, ok := evaluated.(*Duration)
+			if !ok {
+				t.Fatalf("object is not Duration. got=%T (%+v)", evaluated, evaluated)
+			}
+			if result.Value != tt.expected {
+				t.Errorf("object has wrong value. got=%s, want=%s", result.Value, tt.expected)
+			}
+		})
+	}
+}
+
+func TestDurationComparison(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected bool
+	}{
+		{"1 day > 23 hours;", true},
+		{"5m == 300s;", true},
+		{"5m != 300s;", false},
+		{"1ms < 1s;", true},
+		{"1h <= 60 min;", true},
+		{"1h >= 61 min;", false},
+		{"1h == 1;", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			evaluated := testEval(tt.input)
+			testBooleanObject(t, evaluated, tt.expected)
+		})
+	}
+}
+
+func TestDurationRatio(t *testing.T) {
+	evaluated := testEval("1h / 30m;")
+	testFloatObject(t, evaluated, 2)
+}
+
+func TestTimeArithmetic(t *testing.T) {
+	env := NewEnvironment(os.Stdout)
+	start := time.Date(2024, 3, 15, 10, 0, 0, 0, time.UTC)
+	env.Set("t", &Time{Value: start})
+
+	tests := []struct {
+		input    string
+		expected time.Time
+	}{
+		{"t + 30 days;", start.Add(30 * 24 * time.Hour)},
+		{"1h + t;", start.Add(time.Hour)},
+		{"t - 90s;", start.Add(-90 * time.Second)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			program := parser.New(lexer.New(tt.input)).ParseProgram()
+			evaluated := Eval(program, env)
+			result, ok := evaluated.(*Time)
+			if !ok {
+				t.Fatalf("object is not Time. got=%T (%+v)", evaluated, evaluated)
+			}
+			if !result.Value.Equal(tt.expected) {
+				t.Errorf("object has wrong value. got=%s, want=%s", result.Value, tt.expected)
+			}
+		})
+	}
+
+	program := parser.New(lexer.New("(t + 2h) - t;")).ParseProgram()
+	evaluated := Eval(program, env)
+	diff, ok := evaluated.(*Duration)
+	if !ok || diff.Value != 2*time.Hour {
+		t.Errorf("expected Duration 2h, got %T (%+v)", evaluated, evaluated)
+	}
+
+	program = parser.New(lexer.New("t + 1s > t;")).ParseProgram()
+	testBooleanObject(t, Eval(program, env), true)
+}
+
+func TestDurationErrors(t *testing.T) {
+	tests := []struct {
+		input           string
+		expectedMessage string
+	}{
+		{"1h + 5;", "type mismatch: DURATION + INTEGER"},
+		{"5 - 1h;", "type mismatch: INTEGER - DURATION"},
+		{"5 / 1h;", "type mismatch: INTEGER / DURATION"},
+		{`1h * "x";`, "type mismatch: DURATION * STRING"},
+		{"1h / 0;", "division by zero"},
+		{"1h / 0s;", "division by zero"},
+		{"1h * 1h;", "unknown operator: DURATION * DURATION"},
+		{"1h < 5;", "type mismatch: DURATION < INTEGER"},
+		{"10000 weeks * 100;", "duration overflow: 70000d * 100"},
+		{"10000 weeks + 10000 weeks;", "duration overflow: 70000d + 70000d"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			evaluated := testEval(tt.input)
+			testErrorObject(t, evaluated, tt.expectedMessage)
+		})
+	}
+}
+
+func TestInOperator(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected bool
+	}{
+		{`"admin" in ["admin", "dev"];`, true},
+		{`"ops" in ["admin", "dev"];`, false},
+		{`2 in [1, 2, 3];`, true},
+		{`2.5 in [1.5, 2.5];`, true},
+		{`null in [1, null];`, true},
+		{`true in [false];`, false},
+		{`1 in [];`, false},
+		{`"email" in {email: "a@example.com"};`, true},
+		{`"phone" in {email: "a@example.com"};`, false},
+		{`"sub" in "substring";`, true},
+		{`"xyz" in "substring";`, false},
+		{`"" in "anything";`, true},
+		{`user = {roles: ["admin"]}; "admin" in user.roles;`, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			evaluated := testEval(tt.input)
+			testBooleanObject(t, evaluated, tt.expected)
+		})
+	}
+}
+
+func TestNotInOperator(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected bool
+	}{
+		{`"ops" not in ["admin", "dev"];`, true},
+		{`"admin" not in ["admin", "dev"];`, false},
+		{`"phone" not in {email: "a@example.com"};`, true},
+		{`"sub" not in "substring";`, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			evaluated := testEval(tt.input)
+			testBooleanObject(t, evaluated, tt.expected)
+		})
+	}
+}
+
+func TestInOperatorErrors(t *testing.T) {
+	tests := []struct {
+		input           string
+		expectedMessage string
+	}{
+		{`1 in "abc";`, "type mismatch: INTEGER in STRING"},
+		{`1 in {a: 1};`, "type mismatch: INTEGER in HASH"},
+		{`1 in 5;`, "unknown operator: INTEGER in INTEGER"},
+		{`1 not in 5;`, "unknown operator: INTEGER not in INTEGER"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			evaluated := testEval(tt.input)
+			testErrorObject(t, evaluated, tt.expectedMessage)
+		})
+	}
+}
+
 func TestGroupedExpressions(t *testing.T) {
 	tests := []struct {
 		input    string
@@ -721,6 +1023,77 @@ func TestIfElseStatement(t *testing.T) {
 	}
 }
 
+func TestIfStatementNonBooleanCondition(t *testing.T) {
+	tests := []struct {
+		input           string
+		expectedMessage string
+		expectedColumn  int
+	}{
+		{"if (null) { 1; }", "condition must be BOOLEAN, got NULL", 5},
+		{"x = 0;\nif (x) { 1; } else { 2; }", "condition must be BOOLEAN, got INTEGER", 5},
+		{`if ({}.missing) { 1; }`, "condition must be BOOLEAN, got NULL", 5},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			evaluated := testEval(tt.input)
+			if !testErrorObject(t, evaluated, tt.expectedMessage) {
+				return
+			}
+			if errObj := evaluated.(*Error); errObj.Column != tt.expectedColumn {
+				t.Errorf("wrong column. got=%d, want=%d", errObj.Column, tt.expectedColumn)
+			}
+		})
+	}
+}
+
+func TestAssertStatement(t *testing.T) {
+	tests := []string{
+		"assert true;",
+		"assert 1 < 2, \"ordered\";",
+		"x = [1]; assert x == [1];",
+		"assert 1 == 1, 1 / 0;",
+	}
+
+	for _, input := range tests {
+		t.Run(input, func(t *testing.T) {
+			evaluated := testEval(input)
+			testNullObject(t, evaluated)
+		})
+	}
+}
+
+func TestAssertStatementFailure(t *testing.T) {
+	tests := []struct {
+		input            string
+		expectedMessage  string
+		expectedColumn   int
+		expectedCategory ErrorCategory
+	}{
+		{"assert false;", "assertion failed: false", 1, AssertionError},
+		{"x = 3;\n  assert x == 4, \"x is four\";", "assertion failed: (x == 4): x is four", 3, AssertionError},
+		{"assert 1 > 2, [1, 2];", "assertion failed: (1 > 2): [1, 2]", 1, AssertionError},
+		{"assert 1 / 0;", "division by zero", 8, RuntimeError},
+		{"assert false, 1 / 0;", "division by zero", 15, RuntimeError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			evaluated := testEval(tt.input)
+			if !testErrorObject(t, evaluated, tt.expectedMessage) {
+				return
+			}
+			errObj := evaluated.(*Error)
+			if errObj.Column != tt.expectedColumn {
+				t.Errorf("wrong column. got=%d, want=%d", errObj.Column, tt.expectedColumn)
+			}
+			if errObj.Category != tt.expectedCategory {
+				t.Errorf("wrong category. got=%d, want=%d", errObj.Category, tt.expectedCategory)
+			}
+		})
+	}
+}
+
 func TestIfStatementReturnsNull(t *testing.T) {
 	tests := []string{
 		"if (true) { 5; }",
@@ -868,6 +1241,78 @@ func TestListLiteralMixedTypes(t *testing.T) {
 	testNullObject(t, list.Elements[3])
 }
 
+func TestDeepEquality(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected bool
+	}{
+		{"[1, 2] == [1, 2];", true},
+		{"[1, 2] == [2, 1];", false},
+		{"[1, 2] == [1, 2, 3];", false},
+		{"[] == [];", true},
+		{"[[1], [2, [3]]] == [[1], [2, [3]]];", true},
+		{`[1, "a"] != [1, "a"];`, false},
+		{"[1] == [1.0];", false},
+		{"{a: 1, b: 2} == {b: 2, a: 1};", true},
+		{"{a: 1} == {a: 1, b: 2};", false},
+		{"{a: 1} == {a: 2};", false},
+		{"{a: 1} == {b: 1};", false},
+		{"{a: [1, {b: null}]} == {a: [1, {b: null}]};", true},
+		{"{} == {};", true},
+		{"{a: 1} != {a: 1};", false},
+		{"[1] == {a: 1};", false},
+		{"[1s, 2m] == [1000ms, 120s];", true},
+		{"[true, null] == [true, null];", true},
+		{"x = [1]; y = x; x == y;", true},
+		{"[[1, 2]] == [[1, 2]];", true},
+		{"[1, 2] in [[1, 2], [3]];", true},
+		{"{a: 1} in [{a: 1}];", true},
+		{"fn f() { return 1; } f == f;", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			evaluated := testEval(tt.input)
+			testBooleanObject(t, evaluated, tt.expected)
+		})
+	}
+}
+
+func TestHashKeyMatchesEquality(t *testing.T) {
+	pairs := [][2]Object{
+		{&List{Elements: []Object{&Integer{Value: 1}}}, &List{Elements: []Object{&Integer{Value: 1}}}},
+		{NewHash(HashPair{"a", TRUE}, HashPair{"b", NULL}), NewHash(HashPair{"b", NULL}, HashPair{"a", TRUE})},
+		{&Float{Value: 0}, &Float{Value: math.Copysign(0, -1)}},
+		{&Duration{Value: time.Second}, &Duration{Value: 1000 * time.Millisecond}},
+	}
+	for _, pair := range pairs {
+		left, _ := hashKey(pair[0])
+		right, _ := hashKey(pair[1])
+		if !objectsEqual(pair[0], pair[1]) || left != right {
+			t.Errorf("expected %s and %s to be equal with equal hash keys, got %q and %q",
+				pair[0].Inspect(), pair[1].Inspect(), left, right)
+		}
+	}
+
+	distinct := [][2]Object{
+		{&Integer{Value: 1}, &String{Value: "1"}},
+		{&Integer{Value: 1}, &Float{Value: 1}},
+		{&List{Elements: []Object{&String{Value: "a,b"}}}, &List{Elements: []Object{&String{Value: "a"}, &String{Value: "b"}}}},
+		{NULL, &String{Value: "null"}},
+	}
+	for _, pair := range distinct {
+		left, _ := hashKey(pair[0])
+		right, _ := hashKey(pair[1])
+		if objectsEqual(pair[0], pair[1]) || left == right {
+			t.Errorf("expected %s (%s) and %s (%s) to differ", pair[0].Inspect(), left, pair[1].Inspect(), right)
+		}
+	}
+
+	if _, ok := hashKey(&Builtin{Name: "print"}); ok {
+		t.Errorf("expected builtin to be unhashable")
+	}
+}
+
 func TestForStatementBasic(t *testing.T) {
 	input := `
 		sum = 0;
@@ -904,6 +1349,40 @@ func TestForStatementStrings(t *testing.T) {
 	testStringObject(t, evaluated, "abc")
 }
 
+func TestForStatementHashKeys(t *testing.T) {
+	input := `
+		result = "";
+		for (k in {z: 1, a: 2, m: 3}) {
+			result = result + k;
+		}
+		result;
+	`
+	evaluated := testEval(input)
+	testStringObject(t, evaluated, "zam")
+}
+
+func TestHashInspectOrder(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`{sk: "P", pk: "U#1", name: "Alice"};`, "{sk: P, pk: U#1, name: Alice}"},
+		{`{x: {b: 1, a: 2}, list: [{d: 1, c: 2}]};`, "{x: {b: 1, a: 2}, list: [{d: 1, c: 2}]}"},
+		{`{k: k + "!" for k in ["c", "a", "b"]};`, "{c: c!, a: a!, b: b!}"},
+		{`{k: 1 for k in ["a", "b", "a"]};`, "{a: 1, b: 1}"},
+		{`[k for k in {b: 1, a: 2}];`, "[b, a]"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			evaluated := testEval(tt.input)
+			if evaluated.Inspect() != tt.expected {
+				t.Errorf("wrong result. got=%s, want=%s", evaluated.Inspect(), tt.expected)
+			}
+		})
+	}
+}
+
 func TestReturnStatements(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -946,8 +1425,8 @@ func TestObjectLiteralEmpty(t *testing.T) {
 	if !ok {
 		t.Fatalf("expected *Hash, got %T", evaluated)
 	}
-	if len(hash.Pairs) != 0 {
-		t.Errorf("expected 0 pairs, got %d", len(hash.Pairs))
+	if hash.Len() != 0 {
+		t.Errorf("expected 0 pairs, got %d", hash.Len())
 	}
 }
 
@@ -957,8 +1436,8 @@ func TestObjectLiteralBasic(t *testing.T) {
 	if !ok {
 		t.Fatalf("expected *Hash, got %T", evaluated)
 	}
-	if len(hash.Pairs) != 2 {
-		t.Fatalf("expected 2 pairs, got %d", len(hash.Pairs))
+	if hash.Len() != 2 {
+		t.Fatalf("expected 2 pairs, got %d", hash.Len())
 	}
 
 	nameVal, ok := hash.Get("name")
@@ -986,15 +1465,15 @@ func TestObjectLiteralMixedTypes(t *testing.T) {
 	if !ok {
 		t.Fatalf("expected *Hash, got %T", evaluated)
 	}
-	if len(hash.Pairs) != 5 {
-		t.Fatalf("expected 5 pairs, got %d", len(hash.Pairs))
+	if hash.Len() != 5 {
+		t.Fatalf("expected 5 pairs, got %d", hash.Len())
 	}
 
-	testStringObject(t, hash.Pairs["str"], "hello")
-	testIntegerObject(t, hash.Pairs["num"], 42)
-	testFloatObject(t, hash.Pairs["float"], 3.14)
-	testBooleanObject(t, hash.Pairs["bool"], true)
-	testNullObject(t, hash.Pairs["nothing"])
+	testStringObject(t, testHashValue(hash, "str"), "hello")
+	testIntegerObject(t, testHashValue(hash, "num"), 42)
+	testFloatObject(t, testHashValue(hash, "float"), 3.14)
+	testBooleanObject(t, testHashValue(hash, "bool"), true)
+	testNullObject(t, testHashValue(hash, "nothing"))
 }
 
 func TestObjectLiteralWithExpressions(t *testing.T) {
@@ -1008,9 +1487,9 @@ func TestObjectLiteralWithExpressions(t *testing.T) {
 		t.Fatalf("expected *Hash, got %T", evaluated)
 	}
 
-	testIntegerObject(t, hash.Pairs["sum"], 10)
-	testIntegerObject(t, hash.Pairs["product"], 12)
-	testStringObject(t, hash.Pairs["concat"], "hello world")
+	testIntegerObject(t, testHashValue(hash, "sum"), 10)
+	testIntegerObject(t, testHashValue(hash, "product"), 12)
+	testStringObject(t, testHashValue(hash, "concat"), "hello world")
 }
 
 func TestObjectLiteralWithVariables(t *testing.T) {
@@ -1025,8 +1504,8 @@ func TestObjectLiteralWithVariables(t *testing.T) {
 		t.Fatalf("expected *Hash, got %T", evaluated)
 	}
 
-	testIntegerObject(t, hash.Pairs["a"], 10)
-	testStringObject(t, hash.Pairs["b"], "test")
+	testIntegerObject(t, testHashValue(hash, "a"), 10)
+	testStringObject(t, testHashValue(hash, "b"), "test")
 }
 
 func TestObjectLiteralNested(t *testing.T) {
@@ -1095,8 +1574,8 @@ func TestObjectLiteralAssignment(t *testing.T) {
 		t.Fatalf("expected *Hash, got %T", evaluated)
 	}
 
-	testStringObject(t, hash.Pairs["name"], "test")
-	testIntegerObject(t, hash.Pairs["value"], 100)
+	testStringObject(t, testHashValue(hash, "name"), "test")
+	testIntegerObject(t, testHashValue(hash, "value"), 100)
 }
 
 func TestListIndexExpression(t *testing.T) {
@@ -1320,6 +1799,135 @@ func TestHashNestedIndexAccess(t *testing.T) {
 	testIntegerObject(t, evaluated, 42)
 }
 
+func TestListComprehension(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected []int64
+	}{
+		{"[x * 2 for x in [1, 2, 3]];", []int64{2, 4, 6}},
+		{"[x for x in [1, 2, 3, 4] if x > 2];", []int64{3, 4}},
+		{"[x for x in []];", []int64{}},
+		{"[x for x in [1, 2] if false];", []int64{}},
+		{"items = [{n: 1}, {n: 2}]; [i.n for i in items];", []int64{1, 2}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			evaluated := testEval(tt.input)
+			list, ok := evaluated.(*List)
+			if !ok {
+				t.Fatalf("expected *List, got %T (%+v)", evaluated, evaluated)
+			}
+
+			if len(list.Elements) != len(tt.expected) {
+				t.Fatalf("expected %d elements, got %d", len(tt.expected), len(list.Elements))
+			}
+
+			for i, expected := range tt.expected {
+				testIntegerObject(t, list.Elements[i], expected)
+			}
+		})
+	}
+}
+
+func TestComprehensionVariableDoesNotLeak(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected int64
+	}{
+		{"x = 100; [x for x in [1, 2, 3]]; x;", 100},
+		{"x = 100; {\"k\": x for x in [1, 2, 3]}; x;", 100},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			evaluated := testEval(tt.input)
+			testIntegerObject(t, evaluated, tt.expected)
+		})
+	}
+
+	evaluated := testEval("[y for y in [1]]; y;")
+	testErrorObject(t, evaluated, "undefined variable: y")
+}
+
+func TestHashComprehension(t *testing.T) {
+	input := `
+		items = [{pk: "A", n: 1}, {pk: "B", n: 2}, {pk: "C", n: 3}];
+		{i.pk: i.n for i in items if i.n != 2};
+	`
+	evaluated := testEval(input)
+	hash, ok := evaluated.(*Hash)
+	if !ok {
+		t.Fatalf("expected *Hash, got %T (%+v)", evaluated, evaluated)
+	}
+
+	if hash.Len() != 2 {
+		t.Fatalf("expected 2 pairs, got %d", hash.Len())
+	}
+
+	a, _ := hash.Get("A")
+	testIntegerObject(t, a, 1)
+	c, _ := hash.Get("C")
+	testIntegerObject(t, c, 3)
+
+	if _, ok := hash.Get("B"); ok {
+		t.Error("expected key 'B' to be filtered out")
+	}
+}
+
+func TestComprehensionErrors(t *testing.T) {
+	tests := []struct {
+		input           string
+		expectedMessage string
+	}{
+		{"[x for x in 5];", "cannot iterate over INTEGER"},
+		{"{x: x for x in [1, 2]};", "hash key must be STRING, got INTEGER"},
+		{"[x + y for x in [1]];", "undefined variable: y"},
+		{"[x for x in [1] if y];", "undefined variable: y"},
+		{"[x for x in missing];", "undefined variable: missing"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			evaluated := testEval(tt.input)
+			testErrorObject(t, evaluated, tt.expectedMessage)
+		})
+	}
+}
+
+func TestMemberExpression(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected any
+	}{
+		{`{name: "Alice"}.name;`, "Alice"},
+		{`user = {age: 30}; user.age;`, int64(30)},
+		{`{outer: {inner: true}}.outer.inner;`, true},
+		{`{name: "Alice"}.missing;`, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			evaluated := testEval(tt.input)
+			switch expected := tt.expected.(type) {
+			case string:
+				testStringObject(t, evaluated, expected)
+			case int64:
+				testIntegerObject(t, evaluated, expected)
+			case bool:
+				testBooleanObject(t, evaluated, expected)
+			default:
+				testNullObject(t, evaluated)
+			}
+		})
+	}
+}
+
+func TestMemberExpressionUnsupported(t *testing.T) {
+	evaluated := testEval(`x = 5; x.name;`)
+	testErrorObject(t, evaluated, "member access not supported: INTEGER.name")
+}
+
 func TestIndexExpressionTypeErrors(t *testing.T) {
 	tests := []struct {
 		input           string
@@ -4,7 +4,11 @@ package eval
 
 import (
 	"fmt"
+	"math"
+	"regexp"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/boattime/awsl/internal/ast"
 )
@@ -25,6 +29,10 @@ const (
 	FUNCTION_OBJ     = "FUNCTION"
 	RETURN_VALUE_OBJ = "RETURN_VALUE"
 	HASH_OBJ         = "HASH"
+	DURATION_OBJ     = "DURATION"
+	TIME_OBJ         = "TIME"
+	NAMESPACE_OBJ    = "NAMESPACE"
+	REGEX_OBJ        = "REGEX"
 )
 
 // Object is the interface that all runtime values implement.
@@ -63,8 +71,15 @@ type Float struct {
 // Type returns FLOAT_OBJ.
 func (f *Float) Type() ObjectType { return FLOAT_OBJ }
 
-// Inspect returns the float as a string.
-func (f *Float) Inspect() string { return fmt.Sprintf("%g", f.Value) }
+// Inspect returns the float as a string. Exponent notation is only used
+// for magnitudes below 1e-6 or from 1e21, so values in reports such as
+// 1500000 print in full.
+func (f *Float) Inspect() string {
+	if abs := math.Abs(f.Value); abs != 0 && (abs < 1e-6 || abs >= 1e21) {
+		return strconv.FormatFloat(f.Value, 'g', -1, 64)
+	}
+	return strconv.FormatFloat(f.Value, 'f', -1, 64)
+}
 
 // String represents a string value at runtime.
 type String struct {
@@ -99,11 +114,29 @@ func (n *Null) Type() ObjectType { return NULL_OBJ }
 // Inspect returns "null".
 func (n *Null) Inspect() string { return "null" }
 
-// Error represents a runtime error with position information.
+// ErrorCategory classifies an Error so that callers can tell kinds of
+// failure apart, for example to choose a process exit code.
+type ErrorCategory int
+
+const (
+	// RuntimeError is a failure in the script itself, such as a type
+	// mismatch or a bad argument to a builtin. It is the zero value.
+	RuntimeError ErrorCategory = iota
+	// ExitRequest is not a failure: it unwinds evaluation after a call to
+	// exit, carrying the requested ExitCode.
+	ExitRequest
+	// AssertionError is a failed assert statement.
+	AssertionError
+)
+
+// Error represents a runtime error with position information. Errors
+// propagate up through evaluation until they reach the caller of Eval.
 type Error struct {
-	Message string
-	Line    int
-	Column  int
+	Message  string
+	Line     int
+	Column   int
+	Category ErrorCategory
+	ExitCode int // for ExitRequest
 }
 
 // Type returns ERROR_OBJ.
@@ -118,9 +151,14 @@ func (e *Error) Inspect() string {
 type BuiltinFunction func(env *Environment, args ...Object) Object
 
 // Builtin wraps a Go function as an AWSL callable object.
+// Params names the positional parameters that may also be passed as
+// named arguments; a builtin without Params accepts positional arguments
+// only. Optional parameters that are skipped by a named argument are
+// passed as NULL.
 type Builtin struct {
-	Name string
-	Fn   BuiltinFunction
+	Name   string
+	Fn     BuiltinFunction
+	Params []string
 }
 
 // Type returns BUILTIN_OBJ.
@@ -129,6 +167,33 @@ func (b *Builtin) Type() ObjectType { return BUILTIN_OBJ }
 // Inspect returns the builtin function name.
 func (b *Builtin) Inspect() string { return "builtin:" + b.Name }
 
+// Namespace groups related builtins under a name, such as json.parse.
+// Members are reached with member access on the namespace. A namespace
+// with Call can also be called itself, so that log(x) and log.info(msg)
+// can share a name.
+type Namespace struct {
+	Name    string
+	Members map[string]Object
+	Call    *Builtin
+}
+
+// Type returns NAMESPACE_OBJ.
+func (n *Namespace) Type() ObjectType { return NAMESPACE_OBJ }
+
+// Inspect returns the namespace name.
+func (n *Namespace) Inspect() string { return "namespace:" + n.Name }
+
+// Regex is a compiled regular expression, created by re.compile.
+type Regex struct {
+	Value *regexp.Regexp
+}
+
+// Type returns REGEX_OBJ.
+func (r *Regex) Type() ObjectType { return REGEX_OBJ }
+
+// Inspect returns the regex pattern.
+func (r *Regex) Inspect() string { return "regex:" + r.Value.String() }
+
 // List represents a list/array value at runtime.
 type List struct {
 	Elements []Object
@@ -192,26 +257,36 @@ type HashPair struct {
 }
 
 // Hash represents an object/map with string keys.
+// Keys keep the order in which they were first inserted, so printing and
+// iterating a hash is deterministic. Use NewHash and Set to build one.
 type Hash struct {
-	Pairs map[string]Object
+	pairs map[string]Object
+	keys  []string
+}
+
+// NewHash creates a hash containing pairs in the given order.
+func NewHash(pairs ...HashPair) *Hash {
+	h := &Hash{pairs: make(map[string]Object, len(pairs))}
+	for _, pair := range pairs {
+		h.Set(pair.Key, pair.Value)
+	}
+	return h
 }
 
 // Type returns HASH_OBJ.
 func (h *Hash) Type() ObjectType { return HASH_OBJ }
 
-// Inspect returns the hash as a string.
+// Inspect returns the hash as a string, with keys in insertion order.
 func (h *Hash) Inspect() string {
 	var out strings.Builder
 	out.WriteString("{")
-	i := 0
-	for k, v := range h.Pairs {
+	for i, k := range h.keys {
 		if i > 0 {
 			out.WriteString(", ")
 		}
 		out.WriteString(k)
 		out.WriteString(": ")
-		out.WriteString(v.Inspect())
-		i++
+		out.WriteString(h.pairs[k].Inspect())
 	}
 	out.WriteString("}")
 	return out.String()
@@ -219,6 +294,95 @@ func (h *Hash) Inspect() string {
 
 // Get retrieves a value from the hash by key.
 func (h *Hash) Get(key string) (Object, bool) {
-	val, ok := h.Pairs[key]
+	val, ok := h.pairs[key]
 	return val, ok
 }
+
+// Set stores a value in the hash under key. A new key is appended to the
+// key order; replacing the value of an existing key keeps its position.
+func (h *Hash) Set(key string, value Object) {
+	if h.pairs == nil {
+		h.pairs = make(map[string]Object)
+	}
+	if _, exists := h.pairs[key]; !exists {
+		h.keys = append(h.keys, key)
+	}
+	h.pairs[key] = value
+}
+
+// Len returns the number of pairs in the hash.
+func (h *Hash) Len() int { return len(h.keys) }
+
+// Keys returns the keys of the hash in insertion order.
+// The returned slice must not be modified.
+func (h *Hash) Keys() []string { return h.keys }
+
+// Pairs returns the key-value pairs of the hash in insertion order.
+func (h *Hash) Pairs() []HashPair {
+	pairs := make([]HashPair, len(h.keys))
+	for i, k := range h.keys {
+		pairs[i] = HashPair{Key: k, Value: h.pairs[k]}
+	}
+	return pairs
+}
+
+// Duration represents a length of time at runtime.
+type Duration struct {
+	Value time.Duration
+}
+
+// Type returns DURATION_OBJ.
+func (d *Duration) Type() ObjectType { return DURATION_OBJ }
+
+// Inspect returns the duration in compact literal form, e.g. 2h30m or 30d.
+func (d *Duration) Inspect() string {
+	value := d.Value
+	if value == 0 {
+		return "0s"
+	}
+
+	var out strings.Builder
+	if value < 0 {
+		out.WriteString("-")
+		value = -value
+	}
+
+	day := 24 * time.Hour
+	for _, unit := range []struct {
+		length time.Duration
+		suffix string
+	}{{day, "d"}, {time.Hour, "h"}, {time.Minute, "m"}} {
+		if value >= unit.length {
+			out.WriteString(strconv.FormatInt(int64(value/unit.length), 10))
+			out.WriteString(unit.suffix)
+			value %= unit.length
+		}
+	}
+
+	switch {
+	case value == 0:
+	case value%time.Second == 0:
+		out.WriteString(strconv.FormatInt(int64(value/time.Second), 10) + "s")
+	case value < time.Second && value%time.Millisecond == 0:
+		out.WriteString(strconv.FormatInt(int64(value/time.Millisecond), 10) + "ms")
+	case value < time.Millisecond && value%time.Microsecond == 0:
+		out.WriteString(strconv.FormatInt(int64(value/time.Microsecond), 10) + "us")
+	case value < time.Microsecond:
+		out.WriteString(strconv.FormatInt(int64(value), 10) + "ns")
+	default:
+		out.WriteString(strconv.FormatFloat(value.Seconds(), 'f', -1, 64) + "s")
+	}
+
+	return out.String()
+}
+
+// Time represents an instant in time at runtime.
+type Time struct {
+	Value time.Time
+}
+
+// Type returns TIME_OBJ.
+func (t *Time) Type() ObjectType { return TIME_OBJ }
+
+// Inspect returns the time in RFC 3339 format.
+func (t *Time) Inspect() string { return t.Value.Format(time.RFC3339) }
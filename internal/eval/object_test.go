@@ -2,7 +2,9 @@ package eval
 
 import (
 	"os"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/boattime/awsl/internal/ast"
 	"github.com/boattime/awsl/internal/token"
@@ -43,6 +45,11 @@ func TestFloatObject(t *testing.T) {
 		{0.0, FLOAT_OBJ, "0"},
 		{-2.5, FLOAT_OBJ, "-2.5"},
 		{100.001, FLOAT_OBJ, "100.001"},
+		{1e6, FLOAT_OBJ, "1000000"},
+		{1234567.5, FLOAT_OBJ, "1234567.5"},
+		{0.000125, FLOAT_OBJ, "0.000125"},
+		{1e21, FLOAT_OBJ, "1e+21"},
+		{2.5e-7, FLOAT_OBJ, "2.5e-07"},
 	}
 
 	for _, tt := range tests {
@@ -168,6 +175,48 @@ func TestNullSingleton(t *testing.T) {
 	}
 }
 
+func TestDurationObject(t *testing.T) {
+	tests := []struct {
+		value           time.Duration
+		expectedInspect string
+	}{
+		{0, "0s"},
+		{30 * 24 * time.Hour, "30d"},
+		{2*time.Hour + 30*time.Minute, "2h30m"},
+		{90 * time.Second, "1m30s"},
+		{500 * time.Millisecond, "500ms"},
+		{1500 * time.Millisecond, "1.5s"},
+		{250 * time.Microsecond, "250us"},
+		{42 * time.Nanosecond, "42ns"},
+		{-5 * time.Minute, "-5m"},
+		{24*time.Hour + time.Second, "1d1s"},
+	}
+
+	for _, tt := range tests {
+		obj := &Duration{Value: tt.value}
+
+		if obj.Type() != DURATION_OBJ {
+			t.Errorf("Duration.Type() = %q, want %q", obj.Type(), DURATION_OBJ)
+		}
+
+		if obj.Inspect() != tt.expectedInspect {
+			t.Errorf("Duration.Inspect() = %q, want %q", obj.Inspect(), tt.expectedInspect)
+		}
+	}
+}
+
+func TestTimeObject(t *testing.T) {
+	obj := &Time{Value: time.Date(2024, 3, 15, 10, 30, 0, 0, time.UTC)}
+
+	if obj.Type() != TIME_OBJ {
+		t.Errorf("Time.Type() = %q, want %q", obj.Type(), TIME_OBJ)
+	}
+
+	if obj.Inspect() != "2024-03-15T10:30:00Z" {
+		t.Errorf("Time.Inspect() = %q, want %q", obj.Inspect(), "2024-03-15T10:30:00Z")
+	}
+}
+
 func TestErrorObject(t *testing.T) {
 	tests := []struct {
 		message         string
@@ -248,7 +297,7 @@ func TestObjectInterface(t *testing.T) {
 		&Null{},
 		&Error{Message: "test", Line: 1, Column: 1},
 		&List{Elements: []Object{&Integer{Value: 1}}},
-		&Hash{Pairs: map[string]Object{"test": &String{Value: "hello"}}},
+		NewHash(HashPair{"test", &String{Value: "hello"}}),
 		TRUE,
 		FALSE,
 		NULL,
@@ -376,80 +425,97 @@ func TestReturnValueObject(t *testing.T) {
 func TestHashObject(t *testing.T) {
 	tests := []struct {
 		name            string
-		pairs           map[string]Object
+		pairs           []HashPair
 		expectedType    ObjectType
-		expectedInspect []string
+		expectedInspect string
 	}{
 		{
 			name:            "empty hash",
-			pairs:           map[string]Object{},
+			pairs:           nil,
 			expectedType:    HASH_OBJ,
-			expectedInspect: []string{"{}"},
+			expectedInspect: "{}",
 		},
 		{
 			name: "single pair",
-			pairs: map[string]Object{
-				"name": &String{Value: "Alice"},
+			pairs: []HashPair{
+				{"name", &String{Value: "Alice"}},
 			},
 			expectedType:    HASH_OBJ,
-			expectedInspect: []string{"{name: Alice}"},
+			expectedInspect: "{name: Alice}",
 		},
 		{
 			name: "multiple pairs",
-			pairs: map[string]Object{
-				"a": &Integer{Value: 1},
-				"b": &Integer{Value: 2},
+			pairs: []HashPair{
+				{"b", &Integer{Value: 2}},
+				{"a", &Integer{Value: 1}},
 			},
 			expectedType:    HASH_OBJ,
-			expectedInspect: []string{"{a: 1, b: 2}", "{b: 2, a: 1}"},
+			expectedInspect: "{b: 2, a: 1}",
 		},
 		{
 			name: "mixed value types",
-			pairs: map[string]Object{
-				"int":   &Integer{Value: 42},
-				"str":   &String{Value: "hello"},
-				"bool":  TRUE,
-				"float": &Float{Value: 3.14},
+			pairs: []HashPair{
+				{"int", &Integer{Value: 42}},
+				{"str", &String{Value: "hello"}},
+				{"bool", TRUE},
+				{"float", &Float{Value: 3.14}},
 			},
 			expectedType:    HASH_OBJ,
-			expectedInspect: nil,
+			expectedInspect: "{int: 42, str: hello, bool: true, float: 3.14}",
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			obj := &Hash{Pairs: tt.pairs}
+			obj := NewHash(tt.pairs...)
 
 			if obj.Type() != tt.expectedType {
 				t.Errorf("Hash.Type() = %q, want %q", obj.Type(), tt.expectedType)
 			}
 
-			if tt.expectedInspect != nil {
-				inspect := obj.Inspect()
-				found := false
-				for _, expected := range tt.expectedInspect {
-					if inspect == expected {
-						found = true
-						break
-					}
-				}
-				if !found {
-					t.Errorf("Hash.Inspect() = %q, want one of %v", inspect, tt.expectedInspect)
-				}
+			if inspect := obj.Inspect(); inspect != tt.expectedInspect {
+				t.Errorf("Hash.Inspect() = %q, want %q", inspect, tt.expectedInspect)
 			}
 		})
 	}
 }
 
-func TestHashGet(t *testing.T) {
-	hash := &Hash{
-		Pairs: map[string]Object{
-			"name":   &String{Value: "Alice"},
-			"age":    &Integer{Value: 30},
-			"active": TRUE,
-		},
+func TestHashInsertionOrder(t *testing.T) {
+	hash := NewHash()
+	hash.Set("sk", &String{Value: "PROFILE"})
+	hash.Set("pk", &String{Value: "USER#1"})
+	hash.Set("name", &String{Value: "Alice"})
+	hash.Set("sk", &String{Value: "SETTINGS"})
+
+	if hash.Len() != 3 {
+		t.Fatalf("Hash.Len() = %d, want 3", hash.Len())
+	}
+
+	keys := strings.Join(hash.Keys(), ",")
+	if keys != "sk,pk,name" {
+		t.Errorf("Hash.Keys() = %q, want %q", keys, "sk,pk,name")
+	}
+
+	pairs := hash.Pairs()
+	if pairs[0].Key != "sk" || pairs[0].Value.Inspect() != "SETTINGS" {
+		t.Errorf("Hash.Pairs()[0] = %s: %s, want sk: SETTINGS", pairs[0].Key, pairs[0].Value.Inspect())
 	}
 
+	expected := "{sk: SETTINGS, pk: USER#1, name: Alice}"
+	for i := 0; i < 10; i++ {
+		if inspect := hash.Inspect(); inspect != expected {
+			t.Fatalf("Hash.Inspect() = %q, want %q", inspect, expected)
+		}
+	}
+}
+
+func TestHashGet(t *testing.T) {
+	hash := NewHash(
+		HashPair{"name", &String{Value: "Alice"}},
+		HashPair{"age", &Integer{Value: 30}},
+		HashPair{"active", TRUE},
+	)
+
 	tests := []struct {
 		key         string
 		expectedOk  bool
@@ -478,17 +544,15 @@ func TestHashGet(t *testing.T) {
 }
 
 func TestHashWithNestedObjects(t *testing.T) {
-	nested := &Hash{
-		Pairs: map[string]Object{
-			"list": &List{Elements: []Object{
-				&Integer{Value: 1},
-				&Integer{Value: 2},
-			}},
-			"hash": &Hash{Pairs: map[string]Object{
-				"inner": &String{Value: "value"},
-			}},
-		},
-	}
+	nested := NewHash(
+		HashPair{"list", &List{Elements: []Object{
+			&Integer{Value: 1},
+			&Integer{Value: 2},
+		}}},
+		HashPair{"hash", NewHash(
+			HashPair{"inner", &String{Value: "value"}},
+		)},
+	)
 
 	if nested.Type() != HASH_OBJ {
 		t.Errorf("Hash.Type() = %q, want %q", nested.Type(), HASH_OBJ)
@@ -4,6 +4,8 @@
 package lexer
 
 import (
+	"strings"
+
 	"github.com/boattime/awsl/internal/token"
 )
 
@@ -21,6 +23,8 @@ type Lexer struct {
 
 // New creates a new Lexer instance for the given input string.
 // The lexer is initialized and ready to produce tokens via NextToken.
+// A shebang line such as #!/usr/bin/env awsl at the very start of the
+// input is skipped, so that scripts can be made executable.
 func New(input string) *Lexer {
 	l := &Lexer{
 		input:  input,
@@ -28,6 +32,11 @@ func New(input string) *Lexer {
 		column: 0,
 	}
 	l.readChar()
+	if strings.HasPrefix(input, "#!") {
+		for l.ch != '\n' && l.ch != 0 {
+			l.readChar()
+		}
+	}
 	return l
 }
 
@@ -38,17 +47,31 @@ func New(input string) *Lexer {
 func (l *Lexer) NextToken() token.Token {
 	var tok token.Token
 
-	l.skipWhitespaceAndComments()
+	if illegal, ok := l.skipWhitespaceAndComments(); !ok {
+		return illegal
+	}
 
 	// Record position at the start of the token
 	startLine := l.line
 	startColumn := l.column
 
+	if l.isDocComment() {
+		return token.Token{
+			Type:    token.DOC_COMMENT,
+			Literal: l.readDocComment(),
+			Line:    startLine,
+			Column:  startColumn,
+		}
+	}
+
 	switch l.ch {
 	case '=':
 		if l.peekChar() == '=' {
 			l.readChar()
 			tok = token.Token{Type: token.EQ, Literal: "==", Line: startLine, Column: startColumn}
+		} else if l.peekChar() == '~' {
+			l.readChar()
+			tok = token.Token{Type: token.MATCH, Literal: "=~", Line: startLine, Column: startColumn}
 		} else {
 			tok = newToken(token.ASSIGN, l.ch, startLine, startColumn)
 		}
@@ -181,24 +204,83 @@ func (l *Lexer) peekChar() byte {
 	return l.input[l.readPosition]
 }
 
-// skipWhitespaceAndComments advances past whitespace and single-line comments.
-// Comments start with // and continue to the end of the line.
-func (l *Lexer) skipWhitespaceAndComments() {
+// peekCharAt returns the character offset positions past the current one
+// without advancing the lexer. peekCharAt(1) is equivalent to peekChar.
+// Returns 0 if past the end of input.
+func (l *Lexer) peekCharAt(offset int) byte {
+	pos := l.position + offset
+	if pos >= len(l.input) {
+		return 0
+	}
+	return l.input[pos]
+}
+
+// skipWhitespaceAndComments advances past whitespace, single-line
+// comments and block comments. Single-line comments start with // and
+// continue to the end of the line; block comments are enclosed in /* */
+// and may nest. Doc comments (///) are left in place to be tokenized.
+// If a block comment is unterminated, it returns an ILLEGAL token
+// positioned at the comment's opening delimiter and false.
+func (l *Lexer) skipWhitespaceAndComments() (token.Token, bool) {
 	for {
 		// Skip whitespace
 		for l.ch == ' ' || l.ch == '\t' || l.ch == '\n' || l.ch == '\r' {
 			l.readChar()
 		}
 
-		// Skip single-line comments
-		if l.ch == '/' && l.peekChar() == '/' {
+		switch {
+		case l.ch == '/' && l.peekChar() == '/' && !l.isDocComment():
 			l.skipLineComment()
-		} else {
-			break
+		case l.ch == '/' && l.peekChar() == '*':
+			startLine, startColumn := l.line, l.column
+			if !l.skipBlockComment() {
+				return token.Token{Type: token.ILLEGAL, Literal: "/*", Line: startLine, Column: startColumn}, false
+			}
+		default:
+			return token.Token{}, true
 		}
 	}
 }
 
+// skipBlockComment advances past a block comment, including any nested
+// block comments. It assumes the lexer is positioned at the opening '/'
+// and reports whether the closing delimiter was found.
+func (l *Lexer) skipBlockComment() bool {
+	depth := 0
+	for l.ch != 0 {
+		switch {
+		case l.ch == '/' && l.peekChar() == '*':
+			depth++
+			l.readChar()
+		case l.ch == '*' && l.peekChar() == '/':
+			depth--
+			l.readChar()
+			if depth == 0 {
+				l.readChar() // consume the closing '/'
+				return true
+			}
+		}
+		l.readChar()
+	}
+	return false
+}
+
+// isDocComment reports whether the lexer is positioned at a /// doc
+// comment. Four or more slashes are an ordinary comment.
+func (l *Lexer) isDocComment() bool {
+	return l.ch == '/' && l.peekChar() == '/' && l.peekCharAt(2) == '/' && l.peekCharAt(3) != '/'
+}
+
+// readDocComment reads a /// doc comment to the end of the line and
+// returns its text without the slashes or a single leading space.
+func (l *Lexer) readDocComment() string {
+	startPosition := l.position + 3
+	l.skipLineComment()
+
+	text := strings.TrimSuffix(l.input[startPosition:l.position], "\r")
+	return strings.TrimPrefix(text, " ")
+}
+
 // skipLineComment advances past a single-line comment.
 // It assumes the lexer is positioned at the first '/'.
 func (l *Lexer) skipLineComment() {
@@ -218,30 +300,161 @@ func (l *Lexer) readIdentifier() string {
 	return l.input[startPosition:l.position]
 }
 
-// readNumber reads a numeric literal (integer or float) starting at
-// the current position. It returns the literal string and the
-// appropriate token type (INT or FLOAT).
+// readNumber reads a numeric literal (integer, float or duration)
+// starting at the current position. It returns the literal string and
+// the appropriate token type (INT, FLOAT or DURATION).
+// Digits may be grouped with underscores (1_000_000); hexadecimal
+// integers (0xFF) and scientific notation (1.5e3) are also accepted.
 func (l *Lexer) readNumber() (string, token.TokenType) {
 	startPosition := l.position
-	tokenType := token.INT
 
-	// Read integer part
+	if l.ch == '0' && (l.peekChar() == 'x' || l.peekChar() == 'X') && isHexDigit(l.peekCharAt(2)) {
+		l.readChar() // consume the '0'
+		l.readChar() // consume the 'x'
+		for isHexDigit(l.ch) || l.ch == '_' && isHexDigit(l.peekChar()) {
+			l.readChar()
+		}
+		return l.input[startPosition:l.position], token.INT
+	}
+
+	tokenType := l.readDecimal()
+
+	if l.readExponent() {
+		return l.input[startPosition:l.position], token.FLOAT
+	}
+
+	// A spaced unit ends the literal: 30 days, 5 min
+	if l.readSpacedDurationUnit() {
+		return l.input[startPosition:l.position], token.DURATION
+	}
+
+	if !l.readAttachedDurationUnit() {
+		return l.input[startPosition:l.position], tokenType
+	}
+
+	// Attached units may be chained: 2h30m, 1m30s
 	for isDigit(l.ch) {
-		l.readChar()
+		l.readDecimal()
+		if !l.readAttachedDurationUnit() {
+			return l.input[startPosition:l.position], token.ILLEGAL
+		}
 	}
 
+	return l.input[startPosition:l.position], token.DURATION
+}
+
+// readDecimal reads the digits of an integer or float, returning INT
+// or FLOAT depending on whether a fractional part was present.
+func (l *Lexer) readDecimal() token.TokenType {
+	tokenType := token.INT
+
+	// Read integer part
+	l.readDigits()
+
 	// Check for decimal point followed by digits (float)
 	if l.ch == '.' && isDigit(l.peekChar()) {
 		tokenType = token.FLOAT
 		l.readChar() // consume the '.'
 
 		// Read fractional part
-		for isDigit(l.ch) {
-			l.readChar()
-		}
+		l.readDigits()
 	}
 
-	return l.input[startPosition:l.position], tokenType
+	return tokenType
+}
+
+// readDigits reads a run of decimal digits, allowing single underscores
+// between digits as separators.
+func (l *Lexer) readDigits() {
+	for isDigit(l.ch) || l.ch == '_' && isDigit(l.peekChar()) {
+		l.readChar()
+	}
+}
+
+// readExponent consumes an exponent such as e3, E-2 or e+10 and reports
+// whether one was present. Nothing is consumed if the 'e' is not
+// followed by digits.
+func (l *Lexer) readExponent() bool {
+	if l.ch != 'e' && l.ch != 'E' {
+		return false
+	}
+
+	digitOffset := 1
+	if l.peekChar() == '+' || l.peekChar() == '-' {
+		digitOffset = 2
+	}
+	if !isDigit(l.peekCharAt(digitOffset)) {
+		return false
+	}
+
+	for i := 0; i < digitOffset; i++ {
+		l.readChar()
+	}
+	l.readDigits()
+	return true
+}
+
+// readAttachedDurationUnit consumes a time unit written directly after a
+// number (the "h" in 2h). It reports whether a unit was consumed; nothing
+// is consumed if the following word is not a known unit.
+func (l *Lexer) readAttachedDurationUnit() bool {
+	end := l.scanUnitWord(l.position)
+	if end == l.position {
+		return false
+	}
+
+	if _, ok := token.LookupDurationUnit(l.input[l.position:end]); !ok {
+		return false
+	}
+
+	l.advanceTo(end)
+	return true
+}
+
+// readSpacedDurationUnit consumes spaces followed by a multi-letter time
+// unit (the " days" in 30 days). Units never span lines. It reports
+// whether a unit was consumed; nothing is consumed otherwise.
+func (l *Lexer) readSpacedDurationUnit() bool {
+	start := l.position
+	for start < len(l.input) && (l.input[start] == ' ' || l.input[start] == '\t') {
+		start++
+	}
+	if start == l.position {
+		return false
+	}
+
+	end := l.scanUnitWord(start)
+	if end-start < 2 || (end < len(l.input) && isDigit(l.input[end])) {
+		return false
+	}
+
+	if _, ok := token.LookupDurationUnit(l.input[start:end]); !ok {
+		return false
+	}
+
+	l.advanceTo(end)
+	return true
+}
+
+// scanUnitWord returns the end of the run of ASCII letters starting at
+// start. A run followed by an underscore is part of a longer identifier
+// and is reported as empty.
+func (l *Lexer) scanUnitWord(start int) int {
+	end := start
+	for end < len(l.input) && ('a' <= l.input[end] && l.input[end] <= 'z' || 'A' <= l.input[end] && l.input[end] <= 'Z') {
+		end++
+	}
+	if end < len(l.input) && l.input[end] == '_' {
+		return start
+	}
+	return end
+}
+
+// advanceTo reads characters until the lexer is positioned at end.
+func (l *Lexer) advanceTo(end int) {
+	for l.position < end {
+		l.readChar()
+	}
 }
 
 // readString reads a string literal, returning the content without
@@ -279,3 +492,8 @@ func isLetter(ch byte) bool {
 func isDigit(ch byte) bool {
 	return '0' <= ch && ch <= '9'
 }
+
+// isHexDigit reports whether the character is a hexadecimal digit.
+func isHexDigit(ch byte) bool {
+	return isDigit(ch) || 'a' <= ch && ch <= 'f' || 'A' <= ch && ch <= 'F'
+}
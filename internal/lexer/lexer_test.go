@@ -7,7 +7,7 @@ import (
 )
 
 func TestNextToken_Operators(t *testing.T) {
-	input := `= + - ! * / < > == != <= >= && ||`
+	input := `= + - ! * / < > == != =~ <= >= && ||`
 
 	tests := []struct {
 		expectedType    token.TokenType
@@ -23,6 +23,7 @@ func TestNextToken_Operators(t *testing.T) {
 		{token.GT, ">"},
 		{token.EQ, "=="},
 		{token.NOT_EQ, "!="},
+		{token.MATCH, "=~"},
 		{token.LTE, "<="},
 		{token.GTE, ">="},
 		{token.AND, "&&"},
@@ -86,7 +87,7 @@ func TestNextToken_Delimiters(t *testing.T) {
 }
 
 func TestNextToken_Keywords(t *testing.T) {
-	input := `fn true false null if else for in return profile region`
+	input := `fn true false null if else for in not return assert profile region`
 
 	tests := []struct {
 		expectedType    token.TokenType
@@ -100,7 +101,9 @@ func TestNextToken_Keywords(t *testing.T) {
 		{token.ELSE, "else"},
 		{token.FOR, "for"},
 		{token.IN, "in"},
+		{token.NOT, "not"},
 		{token.RETURN, "return"},
+		{token.ASSERT, "assert"},
 		{token.PROFILE, "profile"},
 		{token.REGION, "region"},
 		{token.EOF, ""},
@@ -188,6 +191,122 @@ func TestNextToken_Numbers(t *testing.T) {
 	}
 }
 
+func TestNextToken_NumberForms(t *testing.T) {
+	input := `1_000_000 0xFF 0Xff_ff 1.5e3 2E-2 1e+10 3.141_592 1_ 2e 0x 1__0`
+
+	tests := []struct {
+		expectedType    token.TokenType
+		expectedLiteral string
+	}{
+		{token.INT, "1_000_000"},
+		{token.INT, "0xFF"},
+		{token.INT, "0Xff_ff"},
+		{token.FLOAT, "1.5e3"},
+		{token.FLOAT, "2E-2"},
+		{token.FLOAT, "1e+10"},
+		{token.FLOAT, "3.141_592"},
+		{token.INT, "1"},
+		{token.IDENT, "_"},
+		{token.INT, "2"},
+		{token.IDENT, "e"},
+		{token.INT, "0"},
+		{token.IDENT, "x"},
+		{token.INT, "1"},
+		{token.IDENT, "__0"},
+		{token.EOF, ""},
+	}
+
+	l := New(input)
+
+	for i, tt := range tests {
+		tok := l.NextToken()
+
+		if tok.Type != tt.expectedType {
+			t.Errorf("tests[%d] - tokentype wrong. expected=%q, got=%q",
+				i, tt.expectedType, tok.Type)
+		}
+
+		if tok.Literal != tt.expectedLiteral {
+			t.Errorf("tests[%d] - literal wrong. expected=%q, got=%q",
+				i, tt.expectedLiteral, tok.Literal)
+		}
+	}
+}
+
+func TestNextToken_Durations(t *testing.T) {
+	input := `30 days 5 min 2h30m 1.5h 500ms 1m30s 7 weeks 1 hour;
+3 s 5abc 2h30 10	seconds 4 days_left`
+
+	tests := []struct {
+		expectedType    token.TokenType
+		expectedLiteral string
+	}{
+		{token.DURATION, "30 days"},
+		{token.DURATION, "5 min"},
+		{token.DURATION, "2h30m"},
+		{token.DURATION, "1.5h"},
+		{token.DURATION, "500ms"},
+		{token.DURATION, "1m30s"},
+		{token.DURATION, "7 weeks"},
+		{token.DURATION, "1 hour"},
+		{token.SEMICOLON, ";"},
+		{token.INT, "3"},
+		{token.IDENT, "s"},
+		{token.INT, "5"},
+		{token.IDENT, "abc"},
+		{token.ILLEGAL, "2h30"},
+		{token.DURATION, "10\tseconds"},
+		{token.INT, "4"},
+		{token.IDENT, "days_left"},
+		{token.EOF, ""},
+	}
+
+	l := New(input)
+
+	for i, tt := range tests {
+		tok := l.NextToken()
+
+		if tok.Type != tt.expectedType {
+			t.Errorf("tests[%d] - tokentype wrong. expected=%q, got=%q",
+				i, tt.expectedType, tok.Type)
+		}
+
+		if tok.Literal != tt.expectedLiteral {
+			t.Errorf("tests[%d] - literal wrong. expected=%q, got=%q",
+				i, tt.expectedLiteral, tok.Literal)
+		}
+	}
+}
+
+func TestNextToken_DurationDoesNotSpanLines(t *testing.T) {
+	input := "5\ndays"
+
+	tests := []struct {
+		expectedType    token.TokenType
+		expectedLiteral string
+		expectedLine    int
+	}{
+		{token.INT, "5", 1},
+		{token.IDENT, "days", 2},
+		{token.EOF, "", 2},
+	}
+
+	l := New(input)
+
+	for i, tt := range tests {
+		tok := l.NextToken()
+
+		if tok.Type != tt.expectedType || tok.Literal != tt.expectedLiteral {
+			t.Errorf("tests[%d] - token wrong. expected=%q %q, got=%q %q",
+				i, tt.expectedType, tt.expectedLiteral, tok.Type, tok.Literal)
+		}
+
+		if tok.Line != tt.expectedLine {
+			t.Errorf("tests[%d] - line wrong. expected=%d, got=%d", i, tt.expectedLine, tok.Line)
+		}
+	}
+}
+
 func TestNextToken_Strings(t *testing.T) {
 	input := `"hello" "us-west-2" "with spaces" ""`
 
@@ -251,6 +370,110 @@ bar`
 	}
 }
 
+func TestNextToken_BlockComments(t *testing.T) {
+	input := `foo /* inline */ bar
+/* spans
+   several lines */
+baz /* outer /* nested */ still outer */ qux
+a/**/b`
+
+	tests := []struct {
+		expectedType    token.TokenType
+		expectedLiteral string
+		expectedLine    int
+	}{
+		{token.IDENT, "foo", 1},
+		{token.IDENT, "bar", 1},
+		{token.IDENT, "baz", 4},
+		{token.IDENT, "qux", 4},
+		{token.IDENT, "a", 5},
+		{token.IDENT, "b", 5},
+		{token.EOF, "", 5},
+	}
+
+	l := New(input)
+
+	for i, tt := range tests {
+		tok := l.NextToken()
+
+		if tok.Type != tt.expectedType {
+			t.Errorf("tests[%d] - tokentype wrong. expected=%q, got=%q",
+				i, tt.expectedType, tok.Type)
+		}
+
+		if tok.Literal != tt.expectedLiteral {
+			t.Errorf("tests[%d] - literal wrong. expected=%q, got=%q",
+				i, tt.expectedLiteral, tok.Literal)
+		}
+
+		if tok.Line != tt.expectedLine {
+			t.Errorf("tests[%d] - line wrong. expected=%d, got=%d",
+				i, tt.expectedLine, tok.Line)
+		}
+	}
+}
+
+func TestNextToken_UnterminatedBlockComment(t *testing.T) {
+	tests := []struct {
+		input          string
+		expectedLine   int
+		expectedColumn int
+	}{
+		{"x /* never closed", 1, 3},
+		{"x\n  /* outer /* inner */ still open", 2, 3},
+	}
+
+	for _, tt := range tests {
+		l := New(tt.input)
+		l.NextToken() // x
+
+		tok := l.NextToken()
+		if tok.Type != token.ILLEGAL || tok.Literal != "/*" {
+			t.Fatalf("input %q: expected ILLEGAL \"/*\", got %s %q", tt.input, tok.Type, tok.Literal)
+		}
+
+		if tok.Line != tt.expectedLine || tok.Column != tt.expectedColumn {
+			t.Errorf("input %q: expected position %d:%d, got %d:%d",
+				tt.input, tt.expectedLine, tt.expectedColumn, tok.Line, tok.Column)
+		}
+	}
+}
+
+func TestNextToken_DocComments(t *testing.T) {
+	input := `/// Doubles a number.
+///
+///Second line.
+//// not a doc comment
+fn`
+
+	tests := []struct {
+		expectedType    token.TokenType
+		expectedLiteral string
+	}{
+		{token.DOC_COMMENT, "Doubles a number."},
+		{token.DOC_COMMENT, ""},
+		{token.DOC_COMMENT, "Second line."},
+		{token.FUNCTION, "fn"},
+		{token.EOF, ""},
+	}
+
+	l := New(input)
+
+	for i, tt := range tests {
+		tok := l.NextToken()
+
+		if tok.Type != tt.expectedType {
+			t.Errorf("tests[%d] - tokentype wrong. expected=%q, got=%q",
+				i, tt.expectedType, tok.Type)
+		}
+
+		if tok.Literal != tt.expectedLiteral {
+			t.Errorf("tests[%d] - literal wrong. expected=%q, got=%q",
+				i, tt.expectedLiteral, tok.Literal)
+		}
+	}
+}
+
 func TestNextToken_CompleteScript(t *testing.T) {
 	input := `profile "production";
 region "us-west-2";
@@ -371,7 +594,7 @@ bar baz`
 }
 
 func TestNextToken_TwoCharOperatorPositions(t *testing.T) {
-	input := `== != <= >=`
+	input := `== != <= >= =~`
 
 	tests := []struct {
 		expectedType   token.TokenType
@@ -381,6 +604,7 @@ func TestNextToken_TwoCharOperatorPositions(t *testing.T) {
 		{token.NOT_EQ, 4},
 		{token.LTE, 7},
 		{token.GTE, 10},
+		{token.MATCH, 13},
 	}
 
 	l := New(input)
@@ -576,6 +800,35 @@ func TestNextToken_IllegalCharacter(t *testing.T) {
 	}
 }
 
+func TestNextToken_Shebang(t *testing.T) {
+	tests := []struct {
+		input          string
+		expectedType   token.TokenType
+		expectedLine   int
+		expectedColumn int
+	}{
+		{"#!/usr/bin/env awsl\nx = 1;", token.IDENT, 2, 1},
+		{"#!/usr/bin/env awsl", token.EOF, 1, 20},
+		{"#!/usr/bin/env awsl\n\n  print(1);", token.IDENT, 3, 3},
+		{" #!/usr/bin/env awsl\n", token.ILLEGAL, 1, 2},
+		{"x = 1;\n#!/usr/bin/env awsl\n", token.IDENT, 1, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			tok := New(tt.input).NextToken()
+
+			if tok.Type != tt.expectedType {
+				t.Errorf("tokentype wrong. expected=%q, got=%q", tt.expectedType, tok.Type)
+			}
+			if tok.Line != tt.expectedLine || tok.Column != tt.expectedColumn {
+				t.Errorf("position wrong. expected=%d:%d, got=%d:%d",
+					tt.expectedLine, tt.expectedColumn, tok.Line, tok.Column)
+			}
+		})
+	}
+}
+
 func TestNextToken_EmptyInput(t *testing.T) {
 	l := New("")
 	tok := l.NextToken()
@@ -3,7 +3,12 @@
 package parser
 
 import (
+	"errors"
 	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/boattime/awsl/internal/ast"
 	"github.com/boattime/awsl/internal/lexer"
@@ -34,6 +39,9 @@ type Parser struct {
 	curToken  token.Token // Current token being examined
 	peekToken token.Token // Next token (one token lookahead)
 
+	curDoc  string // Doc comment preceding curToken
+	peekDoc string // Doc comment preceding peekToken
+
 	errors []*Error
 }
 
@@ -62,9 +70,19 @@ func (p *Parser) HasErrors() bool {
 }
 
 // nextToken advances to the next token in the input.
+// Doc comment tokens are never seen by the grammar; their text is
+// attached to the token that follows them.
 func (p *Parser) nextToken() {
 	p.curToken = p.peekToken
+	p.curDoc = p.peekDoc
+
+	var doc []string
 	p.peekToken = p.lexer.NextToken()
+	for p.peekToken.Type == token.DOC_COMMENT {
+		doc = append(doc, p.peekToken.Literal)
+		p.peekToken = p.lexer.NextToken()
+	}
+	p.peekDoc = strings.Join(doc, "\n")
 }
 
 // curTokenIs reports whether the current token is of the given type.
@@ -110,6 +128,10 @@ func (p *Parser) curError(format string, args ...any) {
 
 // peekError records an error for an unexpected peek token.
 func (p *Parser) peekError(expected token.TokenType) {
+	if p.peekTokenIs(token.ILLEGAL) && p.peekToken.Literal == "/*" {
+		p.addError(p.peekToken.Line, p.peekToken.Column, "unterminated block comment")
+		return
+	}
 	p.addError(
 		p.peekToken.Line,
 		p.peekToken.Column,
@@ -137,6 +159,7 @@ func (p *Parser) synchronize() {
 			token.IF,
 			token.FOR,
 			token.RETURN,
+			token.ASSERT,
 			token.PROFILE,
 			token.REGION:
 			p.nextToken()
@@ -180,6 +203,8 @@ func (p *Parser) parseStatement() ast.Statement {
 		return p.parseForStatement()
 	case token.RETURN:
 		return p.parseReturnStatement()
+	case token.ASSERT:
+		return p.parseAssertStatement()
 	case token.FUNCTION:
 		return p.parseFunctionDeclaration()
 	case token.IDENT:
@@ -387,10 +412,43 @@ func (p *Parser) parseReturnStatement() *ast.ReturnStatement {
 	return stmt
 }
 
+// parseAssertStatement parses assert statements.
+// Grammar: assert_statement = "assert" expr [ "," expr ] ";" ;
+func (p *Parser) parseAssertStatement() *ast.AssertStatement {
+	stmt := &ast.AssertStatement{Token: p.curToken}
+
+	p.nextToken() // Move past 'assert'
+
+	stmt.Condition = p.parseExpression()
+	if stmt.Condition == nil {
+		p.synchronize()
+		return nil
+	}
+
+	if p.peekTokenIs(token.COMMA) {
+		p.nextToken() // Move to comma
+		p.nextToken() // Move past comma
+		stmt.Message = p.parseExpression()
+		if stmt.Message == nil {
+			p.synchronize()
+			return nil
+		}
+	}
+
+	// Expect semicolon
+	if !p.expectPeek(token.SEMICOLON) {
+		p.synchronize()
+		return nil
+	}
+
+	p.nextToken() // Move past semicolon
+	return stmt
+}
+
 // parseFunctionDeclaration parses function definitions.
 // Grammar: function_decl = "fn" identifier "(" [ param_list ] ")" block ;
 func (p *Parser) parseFunctionDeclaration() *ast.FunctionDeclaration {
-	stmt := &ast.FunctionDeclaration{Token: p.curToken}
+	stmt := &ast.FunctionDeclaration{Token: p.curToken, Doc: p.curDoc}
 
 	// Expect function name
 	if !p.expectPeek(token.IDENT) {
@@ -584,15 +642,15 @@ func (p *Parser) parseAnd() ast.Expression {
 	return left
 }
 
-// parseEquality parses equality expressions.
-// Grammar: equality = comparison { ( "==" | "!=" ) comparison } ;
+// parseEquality parses equality and regex match expressions.
+// Grammar: equality = comparison { ( "==" | "!=" | "=~" ) comparison } ;
 func (p *Parser) parseEquality() ast.Expression {
 	left := p.parseComparison()
 	if left == nil {
 		return nil
 	}
 
-	for p.peekTokenIs(token.EQ) || p.peekTokenIs(token.NOT_EQ) {
+	for p.peekTokenIs(token.EQ) || p.peekTokenIs(token.NOT_EQ) || p.peekTokenIs(token.MATCH) {
 		p.nextToken() // Move to operator
 		operator := p.curToken
 
@@ -613,8 +671,8 @@ func (p *Parser) parseEquality() ast.Expression {
 	return left
 }
 
-// parseComparison parses comparison expressions.
-// Grammar: comparison = term { ( "<" | ">" | "<=" | ">=" ) term } ;
+// parseComparison parses comparison and membership expressions.
+// Grammar: comparison = term { ( "<" | ">" | "<=" | ">=" | "in" | "not" "in" ) term } ;
 func (p *Parser) parseComparison() ast.Expression {
 	left := p.parseTerm()
 	if left == nil {
@@ -622,9 +680,19 @@ func (p *Parser) parseComparison() ast.Expression {
 	}
 
 	for p.peekTokenIs(token.LT) || p.peekTokenIs(token.GT) ||
-		p.peekTokenIs(token.LTE) || p.peekTokenIs(token.GTE) {
+		p.peekTokenIs(token.LTE) || p.peekTokenIs(token.GTE) ||
+		p.peekTokenIs(token.IN) || p.peekTokenIs(token.NOT) {
 		p.nextToken() // Move to operator
 		operator := p.curToken
+		literal := operator.Literal
+
+		// 'not' is only valid as the first half of 'not in'
+		if operator.Type == token.NOT {
+			if !p.expectPeek(token.IN) {
+				return nil
+			}
+			literal = "not in"
+		}
 
 		p.nextToken() // Move past operator
 		right := p.parseTerm()
@@ -635,7 +703,7 @@ func (p *Parser) parseComparison() ast.Expression {
 		left = &ast.InfixExpression{
 			Token:    operator,
 			Left:     left,
-			Operator: operator.Literal,
+			Operator: literal,
 			Right:    right,
 		}
 	}
@@ -952,6 +1020,9 @@ func (p *Parser) parsePrimary() ast.Expression {
 	case token.FLOAT:
 		return p.parseFloatLiteral()
 
+	case token.DURATION:
+		return p.parseDurationLiteral()
+
 	case token.STRING:
 		return &ast.StringLiteral{
 			Token: p.curToken,
@@ -984,18 +1055,38 @@ func (p *Parser) parsePrimary() ast.Expression {
 	case token.LBRACE:
 		return p.parseObjectLiteral()
 
+	case token.ILLEGAL:
+		if p.curToken.Literal == "/*" {
+			p.curError("unterminated block comment")
+		} else {
+			p.curError("illegal token %q", p.curToken.Literal)
+		}
+		return nil
+
 	default:
 		p.curError("unexpected token %s", p.curToken.Type)
 		return nil
 	}
 }
 
-// parseIntegerLiteral parses an integer literal.
-func (p *Parser) parseIntegerLiteral() *ast.IntegerLiteral {
+// parseIntegerLiteral parses a decimal or hexadecimal integer literal.
+// Literals that do not fit in 64 bits are reported rather than wrapped.
+func (p *Parser) parseIntegerLiteral() ast.Expression {
 	lit := &ast.IntegerLiteral{Token: p.curToken}
 
-	var value int64
-	_, err := fmt.Sscanf(p.curToken.Literal, "%d", &value)
+	digits := strings.ReplaceAll(p.curToken.Literal, "_", "")
+	base := 10
+	if strings.HasPrefix(digits, "0x") || strings.HasPrefix(digits, "0X") {
+		digits = digits[2:]
+		base = 16
+	}
+
+	value, err := strconv.ParseInt(digits, base, 64)
+	if errors.Is(err, strconv.ErrRange) {
+		p.curError("integer literal %s overflows int64 (max %d); use a string for larger numbers",
+			p.curToken.Literal, int64(math.MaxInt64))
+		return nil
+	}
 	if err != nil {
 		p.curError("could not parse %q as integer", p.curToken.Literal)
 		return nil
@@ -1005,12 +1096,16 @@ func (p *Parser) parseIntegerLiteral() *ast.IntegerLiteral {
 	return lit
 }
 
-// parseFloatLiteral parses a floating-point literal.
-func (p *Parser) parseFloatLiteral() *ast.FloatLiteral {
+// parseFloatLiteral parses a floating-point literal, including
+// scientific notation.
+func (p *Parser) parseFloatLiteral() ast.Expression {
 	lit := &ast.FloatLiteral{Token: p.curToken}
 
-	var value float64
-	_, err := fmt.Sscanf(p.curToken.Literal, "%f", &value)
+	value, err := strconv.ParseFloat(strings.ReplaceAll(p.curToken.Literal, "_", ""), 64)
+	if errors.Is(err, strconv.ErrRange) {
+		p.curError("float literal %s is out of range", p.curToken.Literal)
+		return nil
+	}
 	if err != nil {
 		p.curError("could not parse %q as float", p.curToken.Literal)
 		return nil
@@ -1020,6 +1115,69 @@ func (p *Parser) parseFloatLiteral() *ast.FloatLiteral {
 	return lit
 }
 
+// parseDurationLiteral parses a duration literal such as 30 days or 2h30m.
+func (p *Parser) parseDurationLiteral() ast.Expression {
+	value, err := durationValue(p.curToken.Literal)
+	if err != nil {
+		p.curError("could not parse %q as duration: %s", p.curToken.Literal, err)
+		return nil
+	}
+
+	return &ast.DurationLiteral{Token: p.curToken, Value: value}
+}
+
+// durationValue sums the number/unit groups of a duration literal.
+// Integer groups are computed exactly; fractional groups are rounded
+// to the nearest nanosecond.
+func durationValue(literal string) (time.Duration, error) {
+	var total time.Duration
+	rest := literal
+
+	for rest != "" {
+		numEnd := strings.IndexFunc(rest, func(r rune) bool {
+			return (r < '0' || r > '9') && r != '.' && r != '_'
+		})
+		if numEnd <= 0 {
+			return 0, fmt.Errorf("missing number")
+		}
+		number := strings.ReplaceAll(rest[:numEnd], "_", "")
+		rest = strings.TrimLeft(rest[numEnd:], " \t")
+
+		unitEnd := strings.IndexFunc(rest, func(r rune) bool {
+			return r >= '0' && r <= '9'
+		})
+		if unitEnd < 0 {
+			unitEnd = len(rest)
+		}
+		unit, ok := token.LookupDurationUnit(rest[:unitEnd])
+		if !ok {
+			return 0, fmt.Errorf("unknown unit %q", rest[:unitEnd])
+		}
+		rest = rest[unitEnd:]
+
+		var group time.Duration
+		if n, err := strconv.ParseInt(number, 10, 64); err == nil {
+			if n > math.MaxInt64/int64(unit) {
+				return 0, fmt.Errorf("value out of range")
+			}
+			group = time.Duration(n) * unit
+		} else {
+			f, err := strconv.ParseFloat(number, 64)
+			if err != nil || f*float64(unit) >= math.MaxInt64 {
+				return 0, fmt.Errorf("value out of range")
+			}
+			group = time.Duration(math.Round(f * float64(unit)))
+		}
+
+		if total > math.MaxInt64-group {
+			return 0, fmt.Errorf("value out of range")
+		}
+		total += group
+	}
+
+	return total, nil
+}
+
 // parseGroupedExpression parses a parenthesized expression.
 // Assumes curToken is '(' when called.
 func (p *Parser) parseGroupedExpression() *ast.GroupedExpression {
@@ -1039,10 +1197,10 @@ func (p *Parser) parseGroupedExpression() *ast.GroupedExpression {
 	return expr
 }
 
-// parseListLiteral parses a list/array literal.
-// Grammar: list_literal = "[" [ expr { "," expr } ] "]" ;
+// parseListLiteral parses a list/array literal or a list comprehension.
+// Grammar: list_literal = "[" [ expr { "," expr } ] "]" | list_comp ;
 // Assumes curToken is '[' when called.
-func (p *Parser) parseListLiteral() *ast.ListLiteral {
+func (p *Parser) parseListLiteral() ast.Expression {
 	lit := &ast.ListLiteral{
 		Token:    p.curToken,
 		Elements: []ast.Expression{},
@@ -1060,6 +1218,11 @@ func (p *Parser) parseListLiteral() *ast.ListLiteral {
 	if elem == nil {
 		return nil
 	}
+
+	if p.peekTokenIs(token.FOR) {
+		return p.parseListComprehension(lit.Token, elem)
+	}
+
 	lit.Elements = append(lit.Elements, elem)
 
 	for p.peekTokenIs(token.COMMA) {
@@ -1080,13 +1243,13 @@ func (p *Parser) parseListLiteral() *ast.ListLiteral {
 	return lit
 }
 
-// parseObjectLiteral parses an object literal.
-// Grammar: object_literal = "{" [ pair { "," pair } ] "}" ;
+// parseObjectLiteral parses an object literal or a hash comprehension.
+// Grammar: object_literal = "{" [ pair { "," pair } ] "}" | hash_comp ;
 //
 //	pair = identifier ":" expr ;
 //
 // Assumes curToken is '{' when called.
-func (p *Parser) parseObjectLiteral() *ast.ObjectLiteral {
+func (p *Parser) parseObjectLiteral() ast.Expression {
 	lit := &ast.ObjectLiteral{
 		Token: p.curToken,
 		Pairs: []ast.ObjectPair{},
@@ -1098,12 +1261,37 @@ func (p *Parser) parseObjectLiteral() *ast.ObjectLiteral {
 		return lit
 	}
 
-	// Parse first pair
-	pair := p.parseObjectPair()
-	if pair == nil {
+	p.nextToken() // Move to first key
+
+	// The first key is parsed as an expression because a hash
+	// comprehension allows computed keys: {x.pk: x for x in items}
+	key := p.parseExpression()
+	if key == nil {
+		return nil
+	}
+
+	if !p.expectPeek(token.COLON) {
+		return nil
+	}
+
+	p.nextToken() // Move past ':'
+
+	value := p.parseExpression()
+	if value == nil {
+		return nil
+	}
+
+	if p.peekTokenIs(token.FOR) {
+		return p.parseHashComprehension(lit.Token, key, value)
+	}
+
+	ident, ok := key.(*ast.Identifier)
+	if !ok {
+		pos := key.Pos()
+		p.addError(pos.Line, pos.Column, "expected IDENT as object key, got %s", key.String())
 		return nil
 	}
-	lit.Pairs = append(lit.Pairs, *pair)
+	lit.Pairs = append(lit.Pairs, ast.ObjectPair{Key: ident, Value: value})
 
 	for p.peekTokenIs(token.COMMA) {
 		p.nextToken() // Move past comma
@@ -1151,3 +1339,92 @@ func (p *Parser) parseObjectPair() *ast.ObjectPair {
 
 	return pair
 }
+
+// parseListComprehension parses the remainder of a list comprehension
+// once the element expression has been read.
+// Grammar: list_comp = "[" expr comp_clause "]" ;
+// Assumes curToken is the last token of the element expression.
+func (p *Parser) parseListComprehension(tok token.Token, element ast.Expression) ast.Expression {
+	comp := &ast.ListComprehension{
+		Token:   tok,
+		Element: element,
+	}
+
+	comp.Iterator, comp.Iterable, comp.Condition = p.parseComprehensionClause()
+	if comp.Iterator == nil {
+		return nil
+	}
+
+	if !p.expectPeek(token.RBRACKET) {
+		return nil
+	}
+
+	return comp
+}
+
+// parseHashComprehension parses the remainder of a hash comprehension
+// once the key and value expressions have been read.
+// Grammar: hash_comp = "{" expr ":" expr comp_clause "}" ;
+// Assumes curToken is the last token of the value expression.
+func (p *Parser) parseHashComprehension(tok token.Token, key, value ast.Expression) ast.Expression {
+	comp := &ast.HashComprehension{
+		Token: tok,
+		Key:   key,
+		Value: value,
+	}
+
+	comp.Iterator, comp.Iterable, comp.Condition = p.parseComprehensionClause()
+	if comp.Iterator == nil {
+		return nil
+	}
+
+	if !p.expectPeek(token.RBRACE) {
+		return nil
+	}
+
+	return comp
+}
+
+// parseComprehensionClause parses the for/if clause shared by list and
+// hash comprehensions. The returned iterator is nil if parsing failed.
+// Grammar: comp_clause = "for" identifier "in" expr [ "if" expr ] ;
+// Assumes peekToken is 'for' when called.
+func (p *Parser) parseComprehensionClause() (*ast.Identifier, ast.Expression, ast.Expression) {
+	p.nextToken() // Move to 'for'
+
+	// Expect iterator identifier
+	if !p.expectPeek(token.IDENT) {
+		return nil, nil, nil
+	}
+
+	iterator := &ast.Identifier{
+		Token: p.curToken,
+		Value: p.curToken.Literal,
+	}
+
+	// Expect 'in' keyword
+	if !p.expectPeek(token.IN) {
+		return nil, nil, nil
+	}
+
+	p.nextToken() // Move past 'in'
+
+	iterable := p.parseExpression()
+	if iterable == nil {
+		return nil, nil, nil
+	}
+
+	// Check for optional if clause
+	var condition ast.Expression
+	if p.peekTokenIs(token.IF) {
+		p.nextToken() // Move to 'if'
+		p.nextToken() // Move past 'if'
+
+		condition = p.parseExpression()
+		if condition == nil {
+			return nil, nil, nil
+		}
+	}
+
+	return iterator, iterable, condition
+}
@@ -2,6 +2,7 @@ package parser
 
 import (
 	"testing"
+	"time"
 
 	"github.com/boattime/awsl/internal/ast"
 	"github.com/boattime/awsl/internal/lexer"
@@ -108,6 +109,11 @@ func TestIntegerLiteral(t *testing.T) {
 		{"42;", 42},
 		{"0;", 0},
 		{"12345;", 12345},
+		{"1_000_000;", 1000000},
+		{"0xFF;", 255},
+		{"0x7fff_ffff_ffff_ffff;", 9223372036854775807},
+		{"010;", 10},
+		{"9223372036854775807;", 9223372036854775807},
 	}
 
 	for _, tt := range tests {
@@ -129,6 +135,10 @@ func TestFloatLiteral(t *testing.T) {
 		{"3.14;", 3.14},
 		{"0.5;", 0.5},
 		{"100.001;", 100.001},
+		{"1.5e3;", 1500},
+		{"2E-2;", 0.02},
+		{"1e+10;", 1e10},
+		{"1_000.5;", 1000.5},
 	}
 
 	for _, tt := range tests {
@@ -148,6 +158,37 @@ func TestFloatLiteral(t *testing.T) {
 	}
 }
 
+func TestDurationLiteral(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected time.Duration
+	}{
+		{"30 days;", 30 * 24 * time.Hour},
+		{"5 min;", 5 * time.Minute},
+		{"2h30m;", 2*time.Hour + 30*time.Minute},
+		{"1.5 hours;", 90 * time.Minute},
+		{"500ms;", 500 * time.Millisecond},
+		{"1w2d;", 9 * 24 * time.Hour},
+		{"0.1s;", 100 * time.Millisecond},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			program := parseProgram(t, tt.input)
+			requireStatementCount(t, program, 1)
+
+			expr := requireExpressionStatement(t, program.Statements[0])
+			durLit, ok := expr.(*ast.DurationLiteral)
+			if !ok {
+				t.Fatalf("expected *ast.DurationLiteral, got %T", expr)
+			}
+			if durLit.Value != tt.expected {
+				t.Errorf("expected value %s, got %s", tt.expected, durLit.Value)
+			}
+		})
+	}
+}
+
 func TestStringLiteral(t *testing.T) {
 	tests := []struct {
 		input    string
@@ -285,8 +326,11 @@ func TestInfixExpression(t *testing.T) {
 		{"5 < 5;", 5, "<", 5},
 		{"5 == 5;", 5, "==", 5},
 		{"5 != 5;", 5, "!=", 5},
+		{"5 =~ 5;", 5, "=~", 5},
 		{"5 >= 5;", 5, ">=", 5},
 		{"5 <= 5;", 5, "<=", 5},
+		{"5 in 5;", 5, "in", 5},
+		{"5 not in 5;", 5, "not in", 5},
 	}
 
 	for _, tt := range tests {
@@ -366,6 +410,13 @@ func TestOperatorPrecedence(t *testing.T) {
 		{"2 / (5 + 5);", "(2 / ((5 + 5)))"},
 		{"-(5 + 5);", "(-((5 + 5)))"},
 		{"!(true == true);", "(!((true == true)))"},
+		{"a + b in c;", "((a + b) in c)"},
+		{"a in b == c not in d;", "((a in b) == (c not in d))"},
+		{"!a in b;", "((!a) in b)"},
+		{"a in b && c not in d;", "((a in b) && (c not in d))"},
+		{`"admin" in user.roles;`, `("admin" in (user.roles))`},
+		{`a + b =~ "x" && c;`, `(((a + b) =~ "x") && c)`},
+		{`name =~ "^api-" == true;`, `((name =~ "^api-") == true)`},
 	}
 
 	for _, tt := range tests {
@@ -526,6 +577,101 @@ func TestObjectLiteralNested(t *testing.T) {
 	testIntegerLiteral(t, innerObj.Pairs[0].Value, 42)
 }
 
+func TestListComprehension(t *testing.T) {
+	program := parseProgram(t, `[x.name for x in items if x.active];`)
+	requireStatementCount(t, program, 1)
+
+	expr := requireExpressionStatement(t, program.Statements[0])
+	comp, ok := expr.(*ast.ListComprehension)
+	if !ok {
+		t.Fatalf("expected *ast.ListComprehension, got %T", expr)
+	}
+
+	if _, ok := comp.Element.(*ast.MemberExpression); !ok {
+		t.Errorf("expected element *ast.MemberExpression, got %T", comp.Element)
+	}
+
+	if comp.Iterator.Value != "x" {
+		t.Errorf("expected iterator 'x', got %q", comp.Iterator.Value)
+	}
+
+	testIdentifier(t, comp.Iterable, "items")
+
+	if comp.Condition == nil {
+		t.Fatal("expected condition, got nil")
+	}
+
+	expected := "[(x.name) for x in items if (x.active)]"
+	if comp.String() != expected {
+		t.Errorf("expected %q, got %q", expected, comp.String())
+	}
+}
+
+func TestListComprehensionWithoutCondition(t *testing.T) {
+	program := parseProgram(t, `[x * 2 for x in [1, 2, 3]];`)
+	requireStatementCount(t, program, 1)
+
+	expr := requireExpressionStatement(t, program.Statements[0])
+	comp, ok := expr.(*ast.ListComprehension)
+	if !ok {
+		t.Fatalf("expected *ast.ListComprehension, got %T", expr)
+	}
+
+	if comp.Condition != nil {
+		t.Errorf("expected nil condition, got %s", comp.Condition.String())
+	}
+
+	if _, ok := comp.Iterable.(*ast.ListLiteral); !ok {
+		t.Errorf("expected iterable *ast.ListLiteral, got %T", comp.Iterable)
+	}
+}
+
+func TestHashComprehension(t *testing.T) {
+	program := parseProgram(t, `{x.pk: x for x in items};`)
+	requireStatementCount(t, program, 1)
+
+	expr := requireExpressionStatement(t, program.Statements[0])
+	comp, ok := expr.(*ast.HashComprehension)
+	if !ok {
+		t.Fatalf("expected *ast.HashComprehension, got %T", expr)
+	}
+
+	if _, ok := comp.Key.(*ast.MemberExpression); !ok {
+		t.Errorf("expected key *ast.MemberExpression, got %T", comp.Key)
+	}
+
+	testIdentifier(t, comp.Value, "x")
+
+	if comp.Iterator.Value != "x" {
+		t.Errorf("expected iterator 'x', got %q", comp.Iterator.Value)
+	}
+
+	testIdentifier(t, comp.Iterable, "items")
+
+	if comp.Condition != nil {
+		t.Errorf("expected nil condition, got %s", comp.Condition.String())
+	}
+}
+
+func TestHashComprehensionWithCondition(t *testing.T) {
+	program := parseProgram(t, `{k: 1 for k in keys if k != "sk"};`)
+	requireStatementCount(t, program, 1)
+
+	expr := requireExpressionStatement(t, program.Statements[0])
+	comp, ok := expr.(*ast.HashComprehension)
+	if !ok {
+		t.Fatalf("expected *ast.HashComprehension, got %T", expr)
+	}
+
+	testIdentifier(t, comp.Key, "k")
+	testIntegerLiteral(t, comp.Value, 1)
+
+	expected := `{k: 1 for k in keys if (k != "sk")}`
+	if comp.String() != expected {
+		t.Errorf("expected %q, got %q", expected, comp.String())
+	}
+}
+
 func TestIndexExpression(t *testing.T) {
 	program := parseProgram(t, "items[0];")
 	requireStatementCount(t, program, 1)
@@ -1041,6 +1187,44 @@ func TestReturnStatementWithExpression(t *testing.T) {
 	testIdentifier(t, infix.Right, "b")
 }
 
+func TestAssertStatement(t *testing.T) {
+	program := parseProgram(t, `assert total > 0, "no items";`)
+	requireStatementCount(t, program, 1)
+
+	stmt, ok := program.Statements[0].(*ast.AssertStatement)
+	if !ok {
+		t.Fatalf("expected *ast.AssertStatement, got %T", program.Statements[0])
+	}
+
+	infix, ok := stmt.Condition.(*ast.InfixExpression)
+	if !ok {
+		t.Fatalf("expected *ast.InfixExpression, got %T", stmt.Condition)
+	}
+	testIdentifier(t, infix.Left, "total")
+	testStringLiteral(t, stmt.Message, "no items")
+
+	if stmt.String() != `assert (total > 0), "no items";` {
+		t.Errorf("wrong String(). got=%q", stmt.String())
+	}
+}
+
+func TestAssertStatementWithoutMessage(t *testing.T) {
+	program := parseProgram(t, `assert ok(x);`)
+	requireStatementCount(t, program, 1)
+
+	stmt, ok := program.Statements[0].(*ast.AssertStatement)
+	if !ok {
+		t.Fatalf("expected *ast.AssertStatement, got %T", program.Statements[0])
+	}
+
+	if stmt.Message != nil {
+		t.Error("expected nil message")
+	}
+	if stmt.String() != "assert ok(x);" {
+		t.Errorf("wrong String(). got=%q", stmt.String())
+	}
+}
+
 func TestFunctionDeclarationNoParams(t *testing.T) {
 	program := parseProgram(t, `fn greet() { print("hello"); }`)
 	requireStatementCount(t, program, 1)
@@ -1063,6 +1247,41 @@ func TestFunctionDeclarationNoParams(t *testing.T) {
 	}
 }
 
+func TestFunctionDeclarationDocComment(t *testing.T) {
+	input := `/// Doubles a number.
+/// Returns an int.
+fn double(x) { return x * 2; }
+
+/// Dropped: not followed by a function.
+y = 1;
+
+fn plain() { return 0; }`
+
+	program := parseProgram(t, input)
+	requireStatementCount(t, program, 3)
+
+	double, ok := program.Statements[0].(*ast.FunctionDeclaration)
+	if !ok {
+		t.Fatalf("expected *ast.FunctionDeclaration, got %T", program.Statements[0])
+	}
+	if double.Doc != "Doubles a number.\nReturns an int." {
+		t.Errorf("expected doc for double, got %q", double.Doc)
+	}
+
+	plain, ok := program.Statements[2].(*ast.FunctionDeclaration)
+	if !ok {
+		t.Fatalf("expected *ast.FunctionDeclaration, got %T", program.Statements[2])
+	}
+	if plain.Doc != "" {
+		t.Errorf("expected no doc for plain, got %q", plain.Doc)
+	}
+}
+
+func TestDocCommentInsideExpression(t *testing.T) {
+	program := parseProgram(t, "x = [1, /// ignored\n2];")
+	requireStatementCount(t, program, 1)
+}
+
 func TestFunctionDeclarationWithParams(t *testing.T) {
 	program := parseProgram(t, `fn add(a, b) { return a + b; }`)
 	requireStatementCount(t, program, 1)
@@ -1256,6 +1475,12 @@ func TestParseErrors(t *testing.T) {
 			expectedCount: 1,
 			errorContains: "expected ;",
 		},
+		{
+			name:          "assert without semicolon",
+			input:         `assert x, "msg"`,
+			expectedCount: 1,
+			errorContains: "expected ;",
+		},
 		{
 			name:          "missing closing paren",
 			input:         "foo(;",
@@ -1304,6 +1529,72 @@ func TestParseErrors(t *testing.T) {
 			expectedCount: 1,
 			errorContains: "expected IDENT",
 		},
+		{
+			name:          "integer literal overflow",
+			input:         "x = 99999999999999999999999999999999999999;",
+			expectedCount: 1,
+			errorContains: "integer literal 99999999999999999999999999999999999999 overflows int64",
+		},
+		{
+			name:          "hex literal overflow",
+			input:         "x = 0x1_0000_0000_0000_0000;",
+			expectedCount: 1,
+			errorContains: "overflows int64",
+		},
+		{
+			name:          "float literal out of range",
+			input:         "x = 1e400;",
+			expectedCount: 1,
+			errorContains: "float literal 1e400 is out of range",
+		},
+		{
+			name:          "duration out of range",
+			input:         "x = 1000000 weeks;",
+			expectedCount: 1,
+			errorContains: "could not parse \"1000000 weeks\" as duration",
+		},
+		{
+			name:          "duration missing trailing unit",
+			input:         "x = 2h30;",
+			expectedCount: 1,
+			errorContains: "illegal token \"2h30\"",
+		},
+		{
+			name:          "unterminated block comment",
+			input:         "x = 1;\n/* never closed",
+			expectedCount: 1,
+			errorContains: "unterminated block comment",
+		},
+		{
+			name:          "unterminated block comment after expression",
+			input:         "x = 1 /* never closed",
+			expectedCount: 1,
+			errorContains: "unterminated block comment",
+		},
+		{
+			name:          "not without in",
+			input:         "a not b;",
+			expectedCount: 1,
+			errorContains: "expected IN",
+		},
+		{
+			name:          "object literal non-identifier key",
+			input:         `{"name": 1};`,
+			expectedCount: 1,
+			errorContains: "expected IDENT as object key",
+		},
+		{
+			name:          "comprehension missing in",
+			input:         "[x for x items];",
+			expectedCount: 1,
+			errorContains: "expected IN",
+		},
+		{
+			name:          "comprehension missing closing bracket",
+			input:         "[x for x in items;",
+			expectedCount: 1,
+			errorContains: "expected ]",
+		},
 		{
 			name:          "context missing string",
 			input:         "profile production;",
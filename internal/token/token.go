@@ -2,6 +2,8 @@
 // and parser for lexical analysis.
 package token
 
+import "time"
+
 // TokenType represents the type of a lexical token as a string.
 type TokenType string
 
@@ -36,6 +38,11 @@ const (
 	FLOAT TokenType = "FLOAT"
 	// STRING represents a string literal.
 	STRING TokenType = "STRING"
+	// DURATION represents a number with a time unit suffix (30 days, 2h30m).
+	DURATION TokenType = "DURATION"
+	// DOC_COMMENT represents a /// documentation comment line.
+	// Its literal is the comment text without the leading slashes.
+	DOC_COMMENT TokenType = "DOC_COMMENT"
 )
 
 // Token types for operators.
@@ -50,6 +57,7 @@ const (
 	GT       TokenType = ">"  // Greater than operator
 	EQ       TokenType = "==" // Equality operator
 	NOT_EQ   TokenType = "!=" // Inequality operator
+	MATCH    TokenType = "=~" // Regular expression match operator
 	LTE      TokenType = "<=" // Less than or equal operator
 	GTE      TokenType = ">=" // Greater than or equal operator
 	OR       TokenType = "||" // Logical OR operator
@@ -82,7 +90,9 @@ const (
 	ELSE     TokenType = "ELSE"
 	FOR      TokenType = "FOR"
 	IN       TokenType = "IN"
+	NOT      TokenType = "NOT"
 	RETURN   TokenType = "RETURN"
+	ASSERT   TokenType = "ASSERT"
 	PROFILE  TokenType = "PROFILE"
 	REGION   TokenType = "REGION"
 )
@@ -97,7 +107,9 @@ var keywords = map[string]TokenType{
 	"else":    ELSE,
 	"for":     FOR,
 	"in":      IN,
+	"not":     NOT,
 	"return":  RETURN,
+	"assert":  ASSERT,
 	"profile": PROFILE,
 	"region":  REGION,
 }
@@ -111,3 +123,42 @@ func LookupIdent(ident string) TokenType {
 	}
 	return IDENT
 }
+
+// durationUnits maps time unit suffixes to their length.
+// Single-letter units must be attached to the number (2h30m); longer
+// units may also be separated from it by spaces (30 days, 5 min).
+var durationUnits = map[string]time.Duration{
+	"ns":           time.Nanosecond,
+	"us":           time.Microsecond,
+	"ms":           time.Millisecond,
+	"millisecond":  time.Millisecond,
+	"milliseconds": time.Millisecond,
+	"s":            time.Second,
+	"sec":          time.Second,
+	"secs":         time.Second,
+	"second":       time.Second,
+	"seconds":      time.Second,
+	"m":            time.Minute,
+	"min":          time.Minute,
+	"mins":         time.Minute,
+	"minute":       time.Minute,
+	"minutes":      time.Minute,
+	"h":            time.Hour,
+	"hr":           time.Hour,
+	"hrs":          time.Hour,
+	"hour":         time.Hour,
+	"hours":        time.Hour,
+	"d":            24 * time.Hour,
+	"day":          24 * time.Hour,
+	"days":         24 * time.Hour,
+	"w":            7 * 24 * time.Hour,
+	"week":         7 * 24 * time.Hour,
+	"weeks":        7 * 24 * time.Hour,
+}
+
+// LookupDurationUnit returns the length of the given time unit suffix.
+// The second result reports whether the unit is known.
+func LookupDurationUnit(unit string) (time.Duration, bool) {
+	d, ok := durationUnits[unit]
+	return d, ok
+}